@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Outcomes recorded in AuditEntry.Outcome
+const (
+	AuditTerminated = "terminated"  // the process is gone
+	AuditNotRunning = "not running" // it had exited before the signal
+	AuditRefused    = "refused"     // protected by policy, nothing was sent
+	AuditDenied     = "denied"      // the OS rejected the signal
+	AuditFailed     = "failed"      // any other error, including survivors
+)
+
+// AuditEntry is one line of the kill audit log
+type AuditEntry struct {
+	Time        time.Time `json:"time"`
+	User        string    `json:"user"` // who ran DevPorts Pro
+	PID         int       `json:"pid"`
+	Ports       []int     `json:"ports,omitempty"` // ports the process held
+	Process     string    `json:"process,omitempty"`
+	CommandLine string    `json:"command_line,omitempty"`
+	Signal      string    `json:"signal,omitempty"`   // last signal sent
+	Elevated    bool      `json:"elevated,omitempty"` // sent with administrator rights
+	Outcome     string    `json:"outcome"`
+	Error       string    `json:"error,omitempty"`
+
+	launch *LaunchSpec // offered for restart if the kill succeeds; not logged
+}
+
+// auditMu serialises appends so concurrent kills write whole lines
+var auditMu sync.Mutex
+
+// defaultAuditLogPath keeps the log next to other per-user settings, or
+// returns "" (auditing disabled) when there is no config directory
+func defaultAuditLogPath() string {
+	dir, err := UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "kill-audit.jsonl")
+}
+
+// beginAudit records who is about to kill pid and what it is, before the
+// process and its /proc entry disappear. The same details are kept so the
+// process can be restarted.
+func beginAudit(pid int) *AuditEntry {
+	e := &AuditEntry{Time: time.Now(), PID: pid}
+	if u, err := user.Current(); err == nil {
+		e.User = u.Username
+	}
+	e.Ports = processPorts(strconv.Itoa(pid))
+	if details, err := GetProcessDetails(strconv.Itoa(pid)); err == nil {
+		e.Process = details.Name
+		e.CommandLine = details.CommandLine()
+		e.launch = newLaunchSpec(details, e.Ports)
+	}
+	return e
+}
+
+// processPorts returns the ports pid holds according to the shared snapshot
+func processPorts(pid string) []int {
+	ctx, cancel := context.WithTimeout(context.Background(), AppConfig().CommandTimeout)
+	defer cancel()
+
+	snapshot, err := getProcessSnapshot(ctx)
+	if err != nil {
+		return nil
+	}
+	return snapshot.Ports(pid)
+}
+
+// finish sets the outcome of the attempt and appends the entry to the log.
+// A log that cannot be written does not fail the kill itself.
+func (e *AuditEntry) finish(signal string, err error) {
+	e.Signal = signal
+	switch {
+	case err == nil:
+		e.Outcome = AuditTerminated
+	case errors.Is(err, ErrNoSuchProcess):
+		e.Outcome = AuditNotRunning
+	case errors.Is(err, ErrProtectedProcess):
+		e.Outcome = AuditRefused
+	case errors.Is(err, ErrPermissionDenied):
+		e.Outcome = AuditDenied
+	default:
+		e.Outcome = AuditFailed
+	}
+	if err != nil {
+		e.Error = err.Error()
+	} else {
+		rememberKilled(e.launch)
+	}
+
+	if werr := appendAuditEntry(AppConfig().AuditLogPath, e); werr != nil {
+		fmt.Fprintf(os.Stderr, "devports-pro: failed to write audit log: %v\n", werr)
+	}
+}
+
+// appendAuditEntry writes e as one JSON line. The file is only ever opened
+// for appending.
+func appendAuditEntry(path string, e *AuditEntry) error {
+	if path == "" {
+		return nil
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	auditMu.Lock()
+	defer auditMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadAuditLog returns the audit log entries, newest first. A missing log
+// is empty; lines that do not parse are skipped.
+func ReadAuditLog() ([]AuditEntry, error) {
+	path := AppConfig().AuditLogPath
+	if path == "" {
+		return nil, errors.New("audit log is disabled")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	// Command lines can be long
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e AuditEntry
+		if json.Unmarshal(scanner.Bytes(), &e) == nil {
+			entries = append(entries, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.After(entries[j].Time)
+	})
+	return entries, nil
+}
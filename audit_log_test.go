@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAuditLogRoundTrip(t *testing.T) {
+	start := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	entries := []*AuditEntry{
+		{Time: start, User: "dev", PID: 1234, Ports: []int{3000}, Process: "node", CommandLine: "node server.js", Signal: SignalTerm, Outcome: AuditTerminated},
+		{Time: start.Add(time.Minute), User: "dev", PID: 1, Process: "systemd", Outcome: AuditRefused, Error: "protected process: systemd (PID 1) is a protected process"},
+		{Time: start.Add(2 * time.Minute), User: "dev", PID: 4321, Signal: SignalKill, Elevated: true, Outcome: AuditDenied, Error: "permission denied"},
+	}
+
+	tests := []struct {
+		name  string
+		trail string // written after the entries
+	}{
+		{name: "complete"},
+		{name: "truncated last line", trail: `{"time":"2026-10-16T12:03:00Z","user":"dev","pid":99,"outc`},
+		{name: "corrupt last line", trail: "\x00\x00\x00\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The directory is created on the first append
+			path := filepath.Join(t.TempDir(), "devports-pro", "kill-audit.jsonl")
+			setTestConfig(t, func(c *Config) { c.AuditLogPath = path })
+
+			for _, e := range entries {
+				if err := appendAuditEntry(path, e); err != nil {
+					t.Fatalf("appendAuditEntry() error = %v", err)
+				}
+			}
+			if tt.trail != "" {
+				f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				f.WriteString(tt.trail)
+				f.Close()
+			}
+
+			got, err := ReadAuditLog()
+			if err != nil {
+				t.Fatalf("ReadAuditLog() error = %v", err)
+			}
+			// Newest first
+			want := []AuditEntry{*entries[2], *entries[1], *entries[0]}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ReadAuditLog() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestReadAuditLogMissing(t *testing.T) {
+	setTestConfig(t, func(c *Config) { c.AuditLogPath = filepath.Join(t.TempDir(), "kill-audit.jsonl") })
+	got, err := ReadAuditLog()
+	if err != nil || len(got) != 0 {
+		t.Errorf("ReadAuditLog() = %v, %v, want no entries and no error", got, err)
+	}
+}
+
+func TestAppendAuditEntryDisabled(t *testing.T) {
+	if err := appendAuditEntry("", &AuditEntry{PID: 1}); err != nil {
+		t.Errorf("appendAuditEntry(\"\") error = %v, want nil", err)
+	}
+}
@@ -19,7 +19,7 @@ echo ""
 # Check if we can build for current platform
 echo "Building for current platform ($(go env GOOS)/$(go env GOARCH))..."
 
-if go build -ldflags "-w -s" -o dist/devports-pro-$(go env GOOS)-$(go env GOARCH) main.go port_scanner.go; then
+if go build -ldflags "-w -s" -o dist/devports-pro-$(go env GOOS)-$(go env GOARCH) .; then
     echo "✅ Successfully built for $(go env GOOS)/$(go env GOARCH)"
 else
     echo "❌ Failed to build for $(go env GOOS)/$(go env GOARCH)"
@@ -12,15 +12,15 @@ GOOS=windows GOARCH=amd64 go build -ldflags "-H=windowsgui -w -s" -o dist/devpor
 
 # Build for macOS (64-bit Intel)
 echo "Building for macOS (64-bit Intel)..."  
-GOOS=darwin GOARCH=amd64 go build -ldflags "-w -s" -o dist/devports-pro-macos-intel main.go port_scanner.go
+GOOS=darwin GOARCH=amd64 go build -ldflags "-w -s" -o dist/devports-pro-macos-intel .
 
 # Build for macOS (ARM64 - Apple Silicon)
 echo "Building for macOS (ARM64 - Apple Silicon)..."
-GOOS=darwin GOARCH=arm64 go build -ldflags "-w -s" -o dist/devports-pro-macos-arm64 main.go port_scanner.go
+GOOS=darwin GOARCH=arm64 go build -ldflags "-w -s" -o dist/devports-pro-macos-arm64 .
 
 # Build for Linux (64-bit)
 echo "Building for Linux (64-bit)..."
-GOOS=linux GOARCH=amd64 go build -ldflags "-w -s" -o dist/devports-pro-linux main.go port_scanner.go
+GOOS=linux GOARCH=amd64 go build -ldflags "-w -s" -o dist/devports-pro-linux .
 
 echo "Build complete! Check the dist/ directory for executables."
 echo ""
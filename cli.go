@@ -0,0 +1,354 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+)
+
+// Exit codes returned by RunCLI, so scripts can tell failures apart
+const (
+	ExitOK       = 0 // success
+	ExitFailure  = 1 // the command failed, e.g. the port is still in use
+	ExitUsage    = 2 // invalid command line or configuration
+	ExitNotFound = 3 // no such process, or nothing listening on the port
+	ExitDenied   = 4 // permission denied, or the process is protected
+)
+
+// cliCommandsUsage is appended to the usage printed by ParseFlags
+const cliCommandsUsage = `Commands:
+  list [--json]               list listening ports
+  who PORT                    show the processes holding PORT
+  kill [--force] PID|:PORT    terminate a process, or the processes holding PORT
+  free [--force] PORT         terminate everything holding PORT and check it is released
+  watch [--interval D]        print ports as they open and close until interrupted
+
+Exit codes: 0 success, 1 failure, 2 usage, 3 not found, 4 permission denied or protected
+`
+
+// RunCLI loads the configuration and runs the command in flags.Args,
+// returning the process exit code. Settings saved by the desktop app live
+// in its preferences store and do not apply here.
+func RunCLI(flags *ConfigFlags, stdout, stderr io.Writer) int {
+	if code, done := loadHeadlessConfig(flags, stdout, stderr); done {
+		return code
+	}
+	if len(flags.Args) == 0 {
+		fmt.Fprintf(stderr, "devports: no command given\n\n%s", cliCommandsUsage)
+		return ExitUsage
+	}
+
+	// Ctrl-C stops a scan or watch cleanly instead of killing the process
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cmd, args := flags.Args[0], flags.Args[1:]
+	c := &cli{stdout: stdout, stderr: stderr}
+	switch cmd {
+	case "list":
+		return c.list(ctx, args)
+	case "who":
+		return c.who(args)
+	case "kill":
+		return c.kill(args)
+	case "free":
+		return c.free(args)
+	case "watch":
+		return c.watch(ctx, args)
+	default:
+		fmt.Fprintf(stderr, "devports: unknown command %q\n\n%s", cmd, cliCommandsUsage)
+		return ExitUsage
+	}
+}
+
+// loadHeadlessConfig makes the configuration from files, environment and
+// flags active for the CLI and web modes. done is set with the exit code
+// when the config is invalid or --print-config has been handled.
+func loadHeadlessConfig(flags *ConfigFlags, stdout, stderr io.Writer) (code int, done bool) {
+	cfg, files, err := LoadConfig(flags, nil)
+	if err != nil {
+		fmt.Fprintf(stderr, "devports: %v\n", err)
+		return ExitUsage, true
+	}
+	SetAppConfig(cfg)
+
+	if flags.PrintConfig {
+		if err := PrintConfig(stdout, cfg, files); err != nil {
+			fmt.Fprintf(stderr, "devports: %v\n", err)
+			return ExitFailure, true
+		}
+		return ExitOK, true
+	}
+	return ExitOK, false
+}
+
+// cli holds the output streams shared by the commands
+type cli struct {
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// fail reports err and maps it to an exit code
+func (c *cli) fail(err error) int {
+	fmt.Fprintf(c.stderr, "devports: %v\n", err)
+	return exitCode(err)
+}
+
+// usageError reports an invalid argument
+func (c *cli) usageError(err error) int {
+	fmt.Fprintf(c.stderr, "devports: %v\n", err)
+	return ExitUsage
+}
+
+// exitCode maps the typed errors of the process and port APIs to exit codes
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, ErrNoSuchProcess), errors.Is(err, ErrPortNotInUse):
+		return ExitNotFound
+	case errors.Is(err, ErrPermissionDenied), errors.Is(err, ErrProtectedProcess):
+		return ExitDenied
+	default:
+		return ExitFailure
+	}
+}
+
+// flagSet creates the option parser for a command
+func (c *cli) flagSet(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: %s\n", usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses a command's options and checks it got exactly nargs arguments
+func (c *cli) parse(fs *flag.FlagSet, args []string, nargs int) bool {
+	if err := fs.Parse(args); err != nil {
+		return false
+	}
+	if fs.NArg() != nargs {
+		fs.Usage()
+		return false
+	}
+	return true
+}
+
+// policy picks the kill policy for a command's --force option
+func policy(force bool) string {
+	if force {
+		return KillPolicyForce
+	}
+	return AppConfig().KillPolicy
+}
+
+// parsePort parses a port number argument
+func parsePort(s string) (int, error) {
+	port, err := strconv.Atoi(s)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid port: %q (must be 1-65535)", s)
+	}
+	return port, nil
+}
+
+func (c *cli) list(ctx context.Context, args []string) int {
+	fs := c.flagSet("list", "list [--json]")
+	asJSON := fs.Bool("json", false, "print the ports as JSON")
+	if !c.parse(fs, args, 0) {
+		return ExitUsage
+	}
+
+	// An interrupted scan still prints the ports found so far
+	ports, err := ScanPorts(ctx, ScanOptions{})
+	interrupted := errors.Is(err, context.Canceled)
+	if err != nil && !interrupted {
+		return c.fail(fmt.Errorf("scan failed: %w", err))
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(c.stdout)
+		enc.SetIndent("", "  ")
+		if ports == nil {
+			ports = []PortInfo{}
+		}
+		if err := enc.Encode(ports); err != nil {
+			return c.fail(err)
+		}
+	} else {
+		tw := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "PORT\tPROTO\tADDRESS\tPID\tPROCESS")
+		for _, p := range ports {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", p.Port, p.Protocol, p.LocalAddress, p.PID, p.Process)
+		}
+		tw.Flush()
+	}
+
+	if interrupted {
+		return c.fail(errors.New("scan interrupted, the list is incomplete"))
+	}
+	return ExitOK
+}
+
+func (c *cli) who(args []string) int {
+	fs := c.flagSet("who", "who PORT")
+	if !c.parse(fs, args, 1) {
+		return ExitUsage
+	}
+	port, err := parsePort(fs.Arg(0))
+	if err != nil {
+		return c.usageError(err)
+	}
+
+	pids, err := c.holders(port)
+	if err != nil {
+		return c.fail(err)
+	}
+	for i, pid := range pids {
+		if i > 0 {
+			fmt.Fprintln(c.stdout)
+		}
+		details, err := GetProcessDetails(pid)
+		if err != nil {
+			fmt.Fprintf(c.stdout, "PID: %s\n", pid)
+			fmt.Fprintf(c.stderr, "devports: %v\n", err)
+			continue
+		}
+		fmt.Fprintln(c.stdout, details.String())
+	}
+	return ExitOK
+}
+
+// holders returns the PIDs holding port, or an error when nothing does or
+// the holder cannot be seen
+func (c *cli) holders(port int) ([]string, error) {
+	pids, err := PortHolders(port)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve processes on port %d: %w", port, err)
+	}
+	if len(pids) == 0 {
+		if portListening(port) {
+			return nil, fmt.Errorf("%w: port %d is in use by a process that cannot be identified; try again as root or administrator",
+				ErrPermissionDenied, port)
+		}
+		return nil, fmt.Errorf("%w %d", ErrPortNotInUse, port)
+	}
+	return pids, nil
+}
+
+func (c *cli) kill(args []string) int {
+	fs := c.flagSet("kill", "kill [--force] PID|:PORT")
+	force := fs.Bool("force", false, "kill immediately instead of using the configured kill policy")
+	if !c.parse(fs, args, 1) {
+		return ExitUsage
+	}
+
+	target := fs.Arg(0)
+	pids := []string{target}
+	if portStr, ok := strings.CutPrefix(target, ":"); ok {
+		port, err := parsePort(portStr)
+		if err != nil {
+			return c.usageError(err)
+		}
+		if pids, err = c.holders(port); err != nil {
+			return c.fail(err)
+		}
+	} else if pid, err := strconv.Atoi(target); err != nil || pid <= 0 {
+		return c.usageError(fmt.Errorf("invalid PID: %q (want a PID or :PORT)", target))
+	}
+
+	code := ExitOK
+	for _, pid := range pids {
+		if err := KillProcessWithPolicy(pid, policy(*force)); err != nil {
+			// Report every PID, but exit with the first failure
+			if failed := c.fail(fmt.Errorf("PID %s: %w", pid, err)); code == ExitOK {
+				code = failed
+			}
+			continue
+		}
+		fmt.Fprintf(c.stdout, "Terminated PID %s\n", pid)
+	}
+	return code
+}
+
+func (c *cli) free(args []string) int {
+	fs := c.flagSet("free", "free [--force] PORT")
+	force := fs.Bool("force", false, "kill immediately instead of using the configured kill policy")
+	if !c.parse(fs, args, 1) {
+		return ExitUsage
+	}
+	port, err := parsePort(fs.Arg(0))
+	if err != nil {
+		return c.usageError(err)
+	}
+
+	if err := KillPortWithPolicy(port, policy(*force)); err != nil {
+		return c.fail(err)
+	}
+	fmt.Fprintf(c.stdout, "Port %d is free\n", port)
+	return ExitOK
+}
+
+func (c *cli) watch(ctx context.Context, args []string) int {
+	fs := c.flagSet("watch", "watch [--interval D]")
+	interval := fs.Duration("interval", 5*time.Second, "time between scans")
+	if !c.parse(fs, args, 0) {
+		return ExitUsage
+	}
+	if *interval <= 0 {
+		return c.usageError(fmt.Errorf("invalid interval: %v (must be > 0)", *interval))
+	}
+
+	var previous map[portKey]PortInfo
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		ports, err := ScanPorts(ctx, ScanOptions{})
+		if ctx.Err() != nil {
+			return ExitOK
+		}
+		if err != nil {
+			fmt.Fprintf(c.stderr, "devports: scan failed: %v\n", err)
+		} else {
+			current := make(map[portKey]PortInfo, len(ports))
+			for _, p := range ports {
+				current[portKey{p.Protocol, p.LocalAddress, p.Port}] = p
+			}
+			stamp := time.Now().Format("15:04:05")
+			for _, p := range ports {
+				if _, seen := previous[portKey{p.Protocol, p.LocalAddress, p.Port}]; !seen {
+					fmt.Fprintf(c.stdout, "%s + %s\n", stamp, formatPortLine(p))
+				}
+			}
+			for key, p := range previous {
+				if _, open := current[key]; !open {
+					fmt.Fprintf(c.stdout, "%s - %s\n", stamp, formatPortLine(p))
+				}
+			}
+			previous = current
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ExitOK
+		}
+	}
+}
+
+// formatPortLine describes a port on one line for watch
+func formatPortLine(p PortInfo) string {
+	return fmt.Sprintf("%d/%s on %s by %s (PID %s)", p.Port, strings.ToLower(p.Protocol), p.LocalAddress, p.Process, p.PID)
+}
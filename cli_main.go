@@ -0,0 +1,27 @@
+//go:build cli && !web
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// main for the devports-cli build, which has no GUI dependencies and runs
+// over SSH on machines without a display
+func main() {
+	flags, err := ParseFlags(os.Args[1:], os.Stderr)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(ExitOK)
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(ExitUsage)
+	}
+	if flags.Web {
+		os.Exit(RunWeb(flags, os.Stdout, os.Stderr))
+	}
+	os.Exit(RunCLI(flags, os.Stdout, os.Stderr))
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRunCLIExitCodes(t *testing.T) {
+	self := strconv.Itoa(os.Getpid())
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStderr string // part of the error output
+	}{
+		{name: "no command", wantCode: ExitUsage, wantStderr: "no command given"},
+		{name: "unknown command", args: []string{"frobnicate"}, wantCode: ExitUsage, wantStderr: `unknown command "frobnicate"`},
+		{name: "invalid configuration", args: []string{"--workers", "0", "list"}, wantCode: ExitUsage, wantStderr: "NumWorkers"},
+		{name: "unknown option", args: []string{"list", "--yaml"}, wantCode: ExitUsage, wantStderr: "-yaml"},
+		{name: "missing argument", args: []string{"who"}, wantCode: ExitUsage, wantStderr: "Usage: who PORT"},
+		{name: "extra argument", args: []string{"free", "3000", "3001"}, wantCode: ExitUsage, wantStderr: "Usage: free"},
+		{name: "invalid port", args: []string{"who", "70000"}, wantCode: ExitUsage, wantStderr: "invalid port"},
+		{name: "invalid PID", args: []string{"kill", "abc"}, wantCode: ExitUsage, wantStderr: "invalid PID"},
+		{name: "invalid interval", args: []string{"watch", "--interval", "0s"}, wantCode: ExitUsage, wantStderr: "invalid interval"},
+		{name: "protected PID", args: []string{"kill", "1"}, wantCode: ExitDenied, wantStderr: "protected"},
+		{name: "protected PID despite force", args: []string{"kill", "--force", self}, wantCode: ExitDenied, wantStderr: "DevPorts Pro itself"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolateConfig(t)
+			// RunCLI makes the loaded config active
+			setTestConfig(t, func(*Config) {})
+			flags, err := ParseFlags(tt.args, io.Discard)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var stdout, stderr bytes.Buffer
+			if code := RunCLI(flags, &stdout, &stderr); code != tt.wantCode {
+				t.Errorf("RunCLI(%q) = %d, want %d; stderr:\n%s", tt.args, code, tt.wantCode, &stderr)
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("RunCLI(%q) stderr = %q, want it to mention %q", tt.args, &stderr, tt.wantStderr)
+			}
+			if stdout.Len() > 0 {
+				t.Errorf("RunCLI(%q) stdout = %q, want nothing", tt.args, &stdout)
+			}
+		})
+	}
+}
@@ -2,100 +2,195 @@ package main
 
 import (
 	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
 // Config holds all application configuration
 type Config struct {
 	// Scanning configuration
-	PortRangeStart int
-	PortRangeEnd   int
-	NumWorkers     int
-	PortTimeout    time.Duration
-	CommandTimeout time.Duration
+	Ports          string        `toml:"ports" yaml:"ports"` // ports, START-END ranges and PortPresets names, e.g. "web, 27017"
+	NumWorkers     int           `toml:"num_workers" yaml:"num_workers"`
+	PortTimeout    time.Duration `toml:"port_timeout" yaml:"port_timeout"`
+	CommandTimeout time.Duration `toml:"command_timeout" yaml:"command_timeout"`
+	ScanBackend    string        `toml:"scan_backend" yaml:"scan_backend"` // one of ScanBackends; "auto" picks the fastest available
 
 	// Auto-refresh configuration
-	AutoRefreshInterval time.Duration
+	AutoRefreshInterval time.Duration `toml:"auto_refresh_interval" yaml:"auto_refresh_interval"`
+
+	// Kill configuration
+	KillPolicy      string        `toml:"kill_policy" yaml:"kill_policy"`             // KillPolicyGraceful or KillPolicyForce
+	KillGracePeriod time.Duration `toml:"kill_grace_period" yaml:"kill_grace_period"` // how long a graceful kill waits before SIGKILL
+
+	// Protected processes are never killed and show a disabled Kill button.
+	// DevPorts Pro itself is always protected.
+	ProtectedPIDs  []int    `toml:"protected_pids" yaml:"protected_pids"`
+	ProtectedNames []string `toml:"protected_names" yaml:"protected_names"` // process names, compared case-insensitively
+	ProtectedPaths []string `toml:"protected_paths" yaml:"protected_paths"` // executable path globs; a trailing "**" matches any subpath
+	ProtectedUsers []string `toml:"protected_users" yaml:"protected_users"` // owning users such as SYSTEM; a DOMAIN\ prefix is ignored
+
+	// Audit configuration
+	AuditLogPath string `toml:"audit_log_path" yaml:"audit_log_path"` // JSON-lines log of every kill attempt; "" disables it
 
 	// Kill verification configuration
-	KillVerifyAttempts   int
-	KillVerifyBaseDelay  time.Duration
-	PostKillRefreshDelay time.Duration
+	KillVerifyAttempts   int           `toml:"kill_verify_attempts" yaml:"kill_verify_attempts"`
+	KillVerifyBaseDelay  time.Duration `toml:"kill_verify_base_delay" yaml:"kill_verify_base_delay"`
+	PostKillRefreshDelay time.Duration `toml:"post_kill_refresh_delay" yaml:"post_kill_refresh_delay"`
 
 	// UI configuration
-	WindowWidth  float32
-	WindowHeight float32
+	WindowWidth  float32 `toml:"window_width" yaml:"window_width"`
+	WindowHeight float32 `toml:"window_height" yaml:"window_height"`
 }
 
-// Validate checks that all configuration values are within acceptable ranges
-func (c *Config) Validate() error {
-	// Port range validation
-	if c.PortRangeStart < 1 || c.PortRangeStart > 65535 {
-		return fmt.Errorf("invalid PortRangeStart: %d (must be 1-65535)", c.PortRangeStart)
+// FieldError describes one invalid configuration value
+type FieldError struct {
+	Field  string // Config field name, e.g. "Ports", or the file, variable or flag that failed to parse
+	Value  any    // the offending value, nil when there is none to show
+	Reason string // the constraint it breaks, e.g. "must be 1-65535"
+}
+
+func (e FieldError) Error() string {
+	if e.Value == nil {
+		return fmt.Sprintf("%s: %s", e.Field, e.Reason)
+	}
+	if s, ok := e.Value.(string); ok {
+		return fmt.Sprintf("%s = %q: %s", e.Field, s, e.Reason)
+	}
+	return fmt.Sprintf("%s = %v: %s", e.Field, e.Value, e.Reason)
+}
+
+// ValidationError lists every invalid field of a Config, so all of them
+// can be fixed at once
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Fields) == 1 {
+		return "invalid configuration: " + e.Fields[0].Error()
 	}
-	if c.PortRangeEnd < 1 || c.PortRangeEnd > 65535 {
-		return fmt.Errorf("invalid PortRangeEnd: %d (must be 1-65535)", c.PortRangeEnd)
+	lines := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		lines[i] = "  " + field.Error()
 	}
-	if c.PortRangeEnd < c.PortRangeStart {
-		return fmt.Errorf("PortRangeEnd (%d) must be >= PortRangeStart (%d)", c.PortRangeEnd, c.PortRangeStart)
+	return fmt.Sprintf("%d invalid configuration values:\n%s", len(e.Fields), strings.Join(lines, "\n"))
+}
+
+// add records an invalid field
+func (e *ValidationError) add(field string, value any, reason string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Value: value, Reason: reason})
+}
+
+// Validate checks that all configuration values are within acceptable
+// ranges. Every problem is reported in a single *ValidationError.
+func (c *Config) Validate() error {
+	var errs ValidationError
+	c.validate(&errs)
+	if len(errs.Fields) > 0 {
+		return &errs
 	}
+	return nil
+}
 
-	// Worker count validation
-	if c.NumWorkers < 1 {
-		return fmt.Errorf("invalid NumWorkers: %d (must be >= 1)", c.NumWorkers)
+// validate adds every invalid field of c to errs
+func (c *Config) validate(errs *ValidationError) {
+	// Port list validation
+	if _, err := ParsePortSet(c.Ports); err != nil {
+		errs.add("Ports", c.Ports, err.Error())
 	}
-	if c.NumWorkers > 10000 {
-		return fmt.Errorf("invalid NumWorkers: %d (must be <= 10000)", c.NumWorkers)
+
+	// Worker count validation
+	if c.NumWorkers < 1 || c.NumWorkers > 10000 {
+		errs.add("NumWorkers", c.NumWorkers, "must be 1-10000")
 	}
 
 	// Timeout validation
 	if c.PortTimeout <= 0 {
-		return fmt.Errorf("invalid PortTimeout: %v (must be > 0)", c.PortTimeout)
+		errs.add("PortTimeout", c.PortTimeout, "must be > 0")
 	}
 	if c.CommandTimeout <= 0 {
-		return fmt.Errorf("invalid CommandTimeout: %v (must be > 0)", c.CommandTimeout)
+		errs.add("CommandTimeout", c.CommandTimeout, "must be > 0")
+	}
+
+	// Scan backend validation
+	if _, err := NewScanner(c.ScanBackend); err != nil {
+		errs.add("ScanBackend", c.ScanBackend, fmt.Sprintf("must be one of %v", ScanBackends))
 	}
 
 	// Auto-refresh validation
 	if c.AutoRefreshInterval < 10*time.Second {
-		return fmt.Errorf("invalid AutoRefreshInterval: %v (must be >= 10s)", c.AutoRefreshInterval)
+		errs.add("AutoRefreshInterval", c.AutoRefreshInterval, "must be >= 10s")
+	}
+
+	// Kill policy validation
+	if c.KillPolicy != KillPolicyGraceful && c.KillPolicy != KillPolicyForce {
+		errs.add("KillPolicy", c.KillPolicy, fmt.Sprintf("must be one of %v", KillPolicies))
+	}
+	if c.KillGracePeriod <= 0 {
+		errs.add("KillGracePeriod", c.KillGracePeriod, "must be > 0")
+	}
+
+	// Protection validation
+	for _, pid := range c.ProtectedPIDs {
+		if pid < 0 {
+			errs.add("ProtectedPIDs", pid, "must be >= 0")
+		}
+	}
+	for _, pattern := range c.ProtectedPaths {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			errs.add("ProtectedPaths", pattern, err.Error())
+		}
 	}
 
 	// Kill verification validation
 	if c.KillVerifyAttempts < 1 {
-		return fmt.Errorf("invalid KillVerifyAttempts: %d (must be >= 1)", c.KillVerifyAttempts)
+		errs.add("KillVerifyAttempts", c.KillVerifyAttempts, "must be >= 1")
 	}
 	if c.KillVerifyBaseDelay <= 0 {
-		return fmt.Errorf("invalid KillVerifyBaseDelay: %v (must be > 0)", c.KillVerifyBaseDelay)
+		errs.add("KillVerifyBaseDelay", c.KillVerifyBaseDelay, "must be > 0")
 	}
 	if c.PostKillRefreshDelay < 0 {
-		return fmt.Errorf("invalid PostKillRefreshDelay: %v (must be >= 0)", c.PostKillRefreshDelay)
+		errs.add("PostKillRefreshDelay", c.PostKillRefreshDelay, "must be >= 0")
 	}
 
 	// UI validation
 	if c.WindowWidth < 400 {
-		return fmt.Errorf("invalid WindowWidth: %v (must be >= 400)", c.WindowWidth)
+		errs.add("WindowWidth", c.WindowWidth, "must be >= 400")
 	}
 	if c.WindowHeight < 300 {
-		return fmt.Errorf("invalid WindowHeight: %v (must be >= 300)", c.WindowHeight)
+		errs.add("WindowHeight", c.WindowHeight, "must be >= 300")
 	}
-
-	return nil
 }
 
 // DefaultConfig returns the default application configuration
 func DefaultConfig() *Config {
 	return &Config{
 		// Scanning
-		PortRangeStart: 1,
-		PortRangeEnd:   9999,
+		Ports:          DefaultPortPreset,
 		NumWorkers:     500,
 		PortTimeout:    100 * time.Millisecond,
 		CommandTimeout: 5 * time.Second,
+		ScanBackend:    BackendAuto,
 
 		// Auto-refresh
 		AutoRefreshInterval: 5 * time.Minute,
 
+		// Kill
+		KillPolicy:      KillPolicyGraceful,
+		KillGracePeriod: 5 * time.Second,
+
+		// Protection
+		ProtectedPIDs:  []int{1},
+		ProtectedNames: slices.Clone(defaultProtectedNames),
+		ProtectedPaths: slices.Clone(defaultProtectedPaths),
+		ProtectedUsers: slices.Clone(defaultProtectedUsers),
+
+		// Audit
+		AuditLogPath: defaultAuditLogPath(),
+
 		// Kill verification
 		KillVerifyAttempts:   5,
 		KillVerifyBaseDelay:  200 * time.Millisecond,
@@ -107,5 +202,28 @@ func DefaultConfig() *Config {
 	}
 }
 
-// AppConfig is the global configuration instance
-var AppConfig = DefaultConfig()
+// PortSet returns the ports to scan. Validate has already checked Ports;
+// an invalid list yields an empty set.
+func (c *Config) PortSet() PortSet {
+	set, _ := ParsePortSet(c.Ports)
+	return set
+}
+
+// appConfig is the active configuration. Scan workers read it concurrently,
+// so it is replaced as a whole rather than modified in place.
+var appConfig atomic.Pointer[Config]
+
+func init() {
+	appConfig.Store(DefaultConfig())
+}
+
+// AppConfig returns the active configuration. Treat it as read-only and
+// read it once per operation to see a consistent set of values.
+func AppConfig() *Config {
+	return appConfig.Load()
+}
+
+// SetAppConfig atomically replaces the active configuration
+func SetAppConfig(cfg *Config) {
+	appConfig.Store(cfg)
+}
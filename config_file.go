@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config file names, in the order they are looked for. The first existing
+// user file and the first existing project file are loaded.
+var (
+	userConfigNames    = []string{"config.toml", "config.yaml", "config.yml"}
+	projectConfigNames = []string{"devports.toml", "devports.yaml", "devports.yml"}
+)
+
+// UserConfigDir is where DevPorts Pro keeps its per-user files
+func UserConfigDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "devports-pro"), nil
+}
+
+// ConfigFiles returns the config files that exist, lowest precedence first:
+// the user file from UserConfigDir, then the project file in the working
+// directory
+func ConfigFiles() []string {
+	var files []string
+	if dir, err := UserConfigDir(); err == nil {
+		if path, ok := firstExisting(dir, userConfigNames); ok {
+			files = append(files, path)
+		}
+	}
+	if path, ok := firstExisting(".", projectConfigNames); ok {
+		files = append(files, path)
+	}
+	return files
+}
+
+// firstExisting returns the first of names that exists in dir
+func firstExisting(dir string, names []string) (string, bool) {
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// LoadConfig builds the effective configuration, each layer overriding the
+// previous one: DefaultConfig, every file from ConfigFiles, the settings
+// saved in prefs by the Settings dialog, DEVPORTS_* environment variables
+// and finally the given command-line flags. prefs and flags may be nil.
+// Keys a file leaves out keep their earlier value. The result is
+// validated; the files that were loaded are returned either way. Every
+// unreadable file, unparsable variable or flag and invalid value is
+// reported in a single *ValidationError.
+func LoadConfig(flags *ConfigFlags, prefs fyne.Preferences) (*Config, []string, error) {
+	var errs ValidationError
+	files := ConfigFiles()
+	cfg := DefaultConfig()
+	for _, path := range files {
+		if err := mergeConfigFile(cfg, path); err != nil {
+			errs.add(path, nil, err.Error())
+		}
+	}
+	if err := mergeSettings(cfg, prefs); err != nil {
+		errs.add("saved settings", nil, err.Error())
+	}
+	applyEnv(cfg, os.Getenv, &errs)
+	flags.apply(cfg, &errs)
+	cfg.validate(&errs)
+
+	if len(errs.Fields) > 0 {
+		return nil, files, &errs
+	}
+	return cfg, files, nil
+}
+
+// mergeConfigFile decodes a TOML or YAML file over cfg. Keys that do not
+// match a Config field are errors rather than silently ignored, so typos
+// do not go unnoticed.
+func mergeConfigFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		return decodeTOML(cfg, string(data))
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		// An empty file decodes to io.EOF and changes nothing
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+	default:
+		return errors.New("unsupported config format (use .toml or .yaml)")
+	}
+	return nil
+}
+
+// decodeTOML decodes a TOML document over cfg, rejecting unknown keys
+func decodeTOML(cfg *Config, data string) error {
+	md, err := toml.Decode(data, cfg)
+	if err != nil {
+		return err
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, key := range undecoded {
+			keys[i] = key.String()
+		}
+		return fmt.Errorf("unknown keys: %s", strings.Join(keys, ", "))
+	}
+	return nil
+}
+
+// settingsPreferenceKey holds the Settings dialog values as a TOML
+// document, so they decode exactly like a config file
+const settingsPreferenceKey = "settings"
+
+// mergeSettings decodes the settings saved by SaveSettings over cfg
+func mergeSettings(cfg *Config, prefs fyne.Preferences) error {
+	if prefs == nil {
+		return nil
+	}
+	data := prefs.String(settingsPreferenceKey)
+	if data == "" {
+		return nil
+	}
+	return decodeTOML(cfg, data)
+}
+
+// ChangedSettings returns the Settings dialog fields that differ between
+// before and after, keyed like a config file. Saving only these keeps
+// values from config files, environment and flags out of the saved
+// settings, where they would outrank later edits to the files.
+func ChangedSettings(before, after *Config) map[string]any {
+	old := settingsValues(before)
+	changed := make(map[string]any)
+	for key, value := range settingsValues(after) {
+		if value != old[key] {
+			changed[key] = value
+		}
+	}
+	return changed
+}
+
+// settingsValues returns the fields the Settings dialog edits, keyed like
+// a config file
+func settingsValues(cfg *Config) map[string]any {
+	return map[string]any{
+		"ports":                 cfg.Ports,
+		"num_workers":           cfg.NumWorkers,
+		"port_timeout":          cfg.PortTimeout,
+		"command_timeout":       cfg.CommandTimeout,
+		"auto_refresh_interval": cfg.AutoRefreshInterval,
+		"kill_policy":           cfg.KillPolicy,
+		"window_width":          cfg.WindowWidth,
+		"window_height":         cfg.WindowHeight,
+	}
+}
+
+// SaveSettings merges values, keyed like a config file, into the saved
+// settings, so they override the config files on the next LoadConfig.
+// Keys not in values keep their saved value.
+func SaveSettings(prefs fyne.Preferences, values map[string]any) error {
+	settings := make(map[string]any)
+	if data := prefs.String(settingsPreferenceKey); data != "" {
+		// Unreadable saved settings are replaced rather than blocking every save
+		if _, err := toml.Decode(data, &settings); err != nil {
+			settings = make(map[string]any)
+		}
+	}
+	maps.Copy(settings, values)
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(settings); err != nil {
+		return fmt.Errorf("failed to encode settings: %w", err)
+	}
+	prefs.SetString(settingsPreferenceKey, buf.String())
+	return nil
+}
+
+// ClearSettings forgets the saved settings, falling back to config files
+// and defaults
+func ClearSettings(prefs fyne.Preferences) {
+	prefs.RemoveValue(settingsPreferenceKey)
+}
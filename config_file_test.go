@@ -0,0 +1,170 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMergeConfigFileFormats(t *testing.T) {
+	want := DefaultConfig()
+	want.Ports = "web, 27017"
+	want.NumWorkers = 64
+	want.PortTimeout = 250 * time.Millisecond
+	want.KillPolicy = KillPolicyForce
+	want.ProtectedNames = []string{"postgres", "sshd"}
+	want.WindowWidth = 1024.5
+
+	files := map[string]string{
+		"config.toml": `ports = "web, 27017"
+num_workers = 64
+port_timeout = "250ms"
+kill_policy = "force"
+protected_names = ["postgres", "sshd"]
+window_width = 1024.5
+`,
+		"config.yaml": `ports: web, 27017
+num_workers: 64
+port_timeout: 250ms
+kill_policy: force
+protected_names: [postgres, sshd]
+window_width: 1024.5
+`,
+		"config.yml": `ports: "web, 27017"
+num_workers: 64
+port_timeout: "250ms"
+kill_policy: force
+protected_names:
+  - postgres
+  - sshd
+window_width: 1024.5
+`,
+	}
+
+	dir := t.TempDir()
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			writeFile(t, path, content)
+
+			cfg := DefaultConfig()
+			if err := mergeConfigFile(cfg, path); err != nil {
+				t.Fatalf("mergeConfigFile() error = %v", err)
+			}
+			if !reflect.DeepEqual(cfg, want) {
+				t.Errorf("mergeConfigFile() = %+v, want %+v", cfg, want)
+			}
+		})
+	}
+}
+
+func TestMergeConfigFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    string // part of the error message
+	}{
+		{name: "unknown TOML key", file: "config.toml", content: "num_worker = 5\n", want: "num_worker"},
+		{name: "unknown nested TOML key", file: "config.toml", content: "[ui]\nwidth = 5\n", want: "ui.width"},
+		{name: "unknown YAML key", file: "config.yaml", content: "portz: web\n", want: "portz"},
+		{name: "wrong type", file: "config.yaml", content: "num_workers: many\n", want: "many"},
+		{name: "unsupported format", file: "config.json", content: "{}", want: "unsupported config format"},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			writeFile(t, path, tt.content)
+
+			err := mergeConfigFile(DefaultConfig(), path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("mergeConfigFile() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeConfigFileEmptyYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, "")
+
+	cfg := DefaultConfig()
+	if err := mergeConfigFile(cfg, path); err != nil {
+		t.Fatalf("mergeConfigFile() error = %v", err)
+	}
+	if !reflect.DeepEqual(cfg, DefaultConfig()) {
+		t.Errorf("mergeConfigFile() = %+v, want the defaults", cfg)
+	}
+}
+
+func TestLoadConfigNamesFileAndKey(t *testing.T) {
+	userDir, workDir := isolateConfig(t)
+	writeFile(t, filepath.Join(userDir, "config.yaml"), "portz: web\n")
+	writeFile(t, filepath.Join(workDir, "devports.toml"), "num_worker = 5\n")
+
+	_, files, err := LoadConfig(nil, nil)
+	if len(files) != 2 {
+		t.Fatalf("LoadConfig() files = %v, want both", files)
+	}
+	if err == nil {
+		t.Fatal("LoadConfig() error = nil, want unknown keys")
+	}
+	for _, want := range []string{filepath.Join(userDir, "config.yaml"), "portz", "devports.toml", "num_worker"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("LoadConfig() error = %q, want it to mention %q", err, want)
+		}
+	}
+}
+
+func TestSaveSettingsRoundTrip(t *testing.T) {
+	before := DefaultConfig()
+	after := DefaultConfig()
+	after.NumWorkers = 64
+	after.AutoRefreshInterval = 90 * time.Second
+	after.KillPolicy = KillPolicyForce
+
+	changed := ChangedSettings(before, after)
+	want := map[string]any{
+		"num_workers":           64,
+		"auto_refresh_interval": 90 * time.Second,
+		"kill_policy":           KillPolicyForce,
+	}
+	if !reflect.DeepEqual(changed, want) {
+		t.Fatalf("ChangedSettings() = %v, want %v", changed, want)
+	}
+
+	prefs := newMemPrefs()
+	if err := SaveSettings(prefs, changed); err != nil {
+		t.Fatalf("SaveSettings() error = %v", err)
+	}
+	// A later save keeps the keys it does not touch
+	if err := SaveSettings(prefs, map[string]any{"port_timeout": 250 * time.Millisecond}); err != nil {
+		t.Fatalf("SaveSettings() error = %v", err)
+	}
+	after.PortTimeout = 250 * time.Millisecond
+
+	saved := prefs.String(settingsPreferenceKey)
+	for _, key := range []string{"ports", "window_width", "command_timeout"} {
+		if strings.Contains(saved, key) {
+			t.Errorf("saved settings %q contain unchanged %s", saved, key)
+		}
+	}
+
+	cfg := DefaultConfig()
+	if err := mergeSettings(cfg, prefs); err != nil {
+		t.Fatalf("mergeSettings() error = %v", err)
+	}
+	if !reflect.DeepEqual(cfg, after) {
+		t.Errorf("mergeSettings() = %+v, want %+v", cfg, after)
+	}
+
+	ClearSettings(prefs)
+	cfg = DefaultConfig()
+	if err := mergeSettings(cfg, prefs); err != nil || !reflect.DeepEqual(cfg, before) {
+		t.Errorf("mergeSettings() after ClearSettings = %+v, %v, want the defaults", cfg, err)
+	}
+}
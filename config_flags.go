@@ -0,0 +1,167 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ConfigFlags holds the command-line options. Config overrides only apply
+// when the flag was given, so unset flags never mask the environment or
+// config files.
+type ConfigFlags struct {
+	Range       string
+	Workers     int
+	Timeout     time.Duration
+	Refresh     time.Duration
+	PrintConfig bool
+	NoGUI       bool
+	Web         bool
+	WebPort     int
+	WebBind     string
+	WebToken    string
+	Args        []string // CLI command and its arguments, after the options
+
+	set map[string]bool // names of the flags given on the command line
+}
+
+// ParseFlags parses the options before the first non-flag argument; the
+// rest is left in Args for the CLI. It returns flag.ErrHelp after printing
+// usage for -h/--help.
+func ParseFlags(args []string, output io.Writer) (*ConfigFlags, error) {
+	f := &ConfigFlags{set: make(map[string]bool)}
+
+	fs := flag.NewFlagSet("devports-pro", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.StringVar(&f.Range, "range", "", "ports to scan: ports, ranges and presets, e.g. 3000-3999,5432,web")
+	fs.IntVar(&f.Workers, "workers", 0, "number of concurrent scanning workers")
+	fs.DurationVar(&f.Timeout, "timeout", 0, "connection timeout per port, e.g. 100ms")
+	fs.DurationVar(&f.Refresh, "refresh", 0, "auto-refresh interval, e.g. 5m")
+	fs.BoolVar(&f.PrintConfig, "print-config", false, "print the effective configuration and exit")
+	fs.BoolVar(&f.NoGUI, "no-gui", false, "run a CLI command instead of the desktop app")
+	fs.BoolVar(&f.Web, "web", false, "serve the web interface and REST API instead of the desktop app")
+	fs.IntVar(&f.WebPort, "port", 8080, "web interface port")
+	fs.StringVar(&f.WebBind, "bind", "127.0.0.1", "web interface bind address; anything but loopback exposes process control to the network")
+	fs.StringVar(&f.WebToken, "token", "", "web API token (default: $"+EnvWebToken+", or a random token)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: devports-pro [OPTIONS]\n")
+		fmt.Fprintf(fs.Output(), "       devports-pro --no-gui [OPTIONS] COMMAND [ARGS]\n")
+		fmt.Fprintf(fs.Output(), "       devports-cli [OPTIONS] COMMAND [ARGS]\n")
+		fmt.Fprintf(fs.Output(), "       devports-web [OPTIONS]\n\nOptions:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(fs.Output(), "\n%s", cliCommandsUsage)
+		fmt.Fprintf(fs.Output(), "\nPrecedence: flags > DEVPORTS_* environment > saved settings > config files > defaults\n")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	f.Args = fs.Args()
+	fs.Visit(func(fl *flag.Flag) { f.set[fl.Name] = true })
+	return f, nil
+}
+
+// apply overrides cfg with the flags that were given, adding the ones that
+// do not parse to errs
+func (f *ConfigFlags) apply(cfg *Config, errs *ValidationError) {
+	if f == nil {
+		return
+	}
+	if f.set["range"] {
+		if _, err := ParsePortSet(f.Range); err != nil {
+			errs.add("--range", f.Range, err.Error())
+		} else {
+			cfg.Ports = f.Range
+		}
+	}
+	if f.set["workers"] {
+		cfg.NumWorkers = f.Workers
+	}
+	if f.set["timeout"] {
+		cfg.PortTimeout = f.Timeout
+	}
+	if f.set["refresh"] {
+		cfg.AutoRefreshInterval = f.Refresh
+	}
+}
+
+// Environment variables read by applyEnv
+const (
+	EnvScanRange       = "DEVPORTS_SCAN_RANGE"       // "3000-9000", or any list ParsePortSet reads
+	EnvTimeout         = "DEVPORTS_TIMEOUT"          // milliseconds, or a duration such as "150ms"
+	EnvRefreshInterval = "DEVPORTS_REFRESH_INTERVAL" // minutes, or a duration such as "90s"
+	EnvWorkers         = "DEVPORTS_WORKERS"
+	EnvScanBackend     = "DEVPORTS_SCAN_BACKEND"
+	EnvKillPolicy      = "DEVPORTS_KILL_POLICY"
+)
+
+// EnvWebToken sets the web API token without exposing it in the process
+// list; it is read by RunWeb rather than applyEnv
+const EnvWebToken = "DEVPORTS_WEB_TOKEN"
+
+// applyEnv overrides cfg with the DEVPORTS_* variables that are set and
+// not empty, adding the ones that do not parse to errs
+func applyEnv(cfg *Config, getenv func(string) string, errs *ValidationError) {
+	if v := getenv(EnvScanRange); v != "" {
+		if _, err := ParsePortSet(v); err != nil {
+			errs.add(EnvScanRange, v, err.Error())
+		} else {
+			cfg.Ports = v
+		}
+	}
+	if v := getenv(EnvTimeout); v != "" {
+		if d, err := parseDurationUnit(v, time.Millisecond); err != nil {
+			errs.add(EnvTimeout, v, "must be milliseconds or a duration such as 150ms")
+		} else {
+			cfg.PortTimeout = d
+		}
+	}
+	if v := getenv(EnvRefreshInterval); v != "" {
+		if d, err := parseDurationUnit(v, time.Minute); err != nil {
+			errs.add(EnvRefreshInterval, v, "must be minutes or a duration such as 90s")
+		} else {
+			cfg.AutoRefreshInterval = d
+		}
+	}
+	if v := getenv(EnvWorkers); v != "" {
+		if n, err := strconv.Atoi(v); err != nil {
+			errs.add(EnvWorkers, v, "must be a number")
+		} else {
+			cfg.NumWorkers = n
+		}
+	}
+	if v := getenv(EnvScanBackend); v != "" {
+		cfg.ScanBackend = v
+	}
+	if v := getenv(EnvKillPolicy); v != "" {
+		cfg.KillPolicy = v
+	}
+}
+
+// parseDurationUnit parses a Go duration, or a bare number in unit
+func parseDurationUnit(s string, unit time.Duration) (time.Duration, error) {
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(n * float64(unit)), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+// PrintConfig writes cfg as TOML, preceded by the files it was loaded
+// from, so the output can be saved as a config file
+func PrintConfig(w io.Writer, cfg *Config, files []string) error {
+	if len(files) == 0 {
+		fmt.Fprintln(w, "# No config files found; showing defaults with overrides")
+	}
+	for _, path := range files {
+		fmt.Fprintf(w, "# Loaded from %s\n", path)
+	}
+	return toml.NewEncoder(w).Encode(cfg)
+}
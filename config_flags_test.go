@@ -0,0 +1,224 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// memPrefs keeps string preferences in memory; LoadConfig and the settings
+// functions use no other kind
+type memPrefs struct {
+	fyne.Preferences
+	values map[string]string
+}
+
+func newMemPrefs() *memPrefs {
+	return &memPrefs{values: make(map[string]string)}
+}
+
+func (p *memPrefs) String(key string) string           { return p.values[key] }
+func (p *memPrefs) SetString(key string, value string) { p.values[key] = value }
+func (p *memPrefs) RemoveValue(key string)             { delete(p.values, key) }
+
+// isolateConfig points the user config directory and working directory at
+// empty temporary directories and clears DEVPORTS_* variables. It returns
+// the user config directory and the working directory.
+func isolateConfig(t *testing.T) (userDir, workDir string) {
+	t.Helper()
+	home := t.TempDir()
+	for _, key := range []string{"XDG_CONFIG_HOME", "HOME", "AppData"} {
+		t.Setenv(key, home)
+	}
+	for _, key := range []string{EnvScanRange, EnvTimeout, EnvRefreshInterval, EnvWorkers, EnvScanBackend, EnvKillPolicy} {
+		t.Setenv(key, "")
+	}
+
+	userDir, err := UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(userDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	workDir = t.TempDir()
+	previous, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(workDir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(previous) })
+	return userDir, workDir
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	tests := []struct {
+		name        string
+		userFile    string // config.toml in the user config directory
+		projectFile string // devports.toml in the working directory
+		settings    string // saved by the Settings dialog
+		env         map[string]string
+		args        []string
+		wantWorkers int
+		wantPorts   string
+	}{
+		{
+			name:        "defaults",
+			wantWorkers: 500,
+			wantPorts:   DefaultPortPreset,
+		},
+		{
+			name:        "user file over defaults",
+			userFile:    "num_workers = 10\n",
+			wantWorkers: 10,
+			wantPorts:   DefaultPortPreset,
+		},
+		{
+			name:        "project file over user file",
+			userFile:    "num_workers = 10\nports = \"web\"\n",
+			projectFile: "num_workers = 20\n",
+			wantWorkers: 20,
+			wantPorts:   "web",
+		},
+		{
+			name:        "saved settings over files",
+			userFile:    "num_workers = 10\n",
+			projectFile: "num_workers = 20\n",
+			settings:    "num_workers = 30\n",
+			wantWorkers: 30,
+			wantPorts:   DefaultPortPreset,
+		},
+		{
+			name:        "environment over saved settings",
+			projectFile: "num_workers = 20\nports = \"web\"\n",
+			settings:    "num_workers = 30\n",
+			env:         map[string]string{EnvWorkers: "40"},
+			wantWorkers: 40,
+			wantPorts:   "web",
+		},
+		{
+			name:        "flags over environment",
+			settings:    "num_workers = 30\n",
+			env:         map[string]string{EnvWorkers: "40", EnvScanRange: "databases"},
+			args:        []string{"--workers", "50"},
+			wantWorkers: 50,
+			wantPorts:   "databases",
+		},
+		{
+			name:        "flags over files",
+			userFile:    "num_workers = 10\nports = \"web\"\n",
+			args:        []string{"--range", "3000-3999"},
+			wantWorkers: 10,
+			wantPorts:   "3000-3999",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userDir, workDir := isolateConfig(t)
+			if tt.userFile != "" {
+				writeFile(t, filepath.Join(userDir, "config.toml"), tt.userFile)
+			}
+			if tt.projectFile != "" {
+				writeFile(t, filepath.Join(workDir, "devports.toml"), tt.projectFile)
+			}
+			prefs := newMemPrefs()
+			if tt.settings != "" {
+				prefs.SetString(settingsPreferenceKey, tt.settings)
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			flags, err := ParseFlags(tt.args, io.Discard)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, _, err := LoadConfig(flags, prefs)
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if cfg.NumWorkers != tt.wantWorkers || cfg.Ports != tt.wantPorts {
+				t.Errorf("LoadConfig() workers = %d, ports = %q, want %d, %q",
+					cfg.NumWorkers, cfg.Ports, tt.wantWorkers, tt.wantPorts)
+			}
+		})
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        map[string]string
+		modify     func(*Config) // expected changes to the defaults
+		wantFields []string      // FieldError.Field of every problem
+	}{
+		{
+			name:   "nothing set",
+			modify: func(*Config) {},
+		},
+		{
+			name: "bare numbers use the documented units",
+			env:  map[string]string{EnvTimeout: "150", EnvRefreshInterval: "2", EnvWorkers: "64"},
+			modify: func(c *Config) {
+				c.PortTimeout = 150 * time.Millisecond
+				c.AutoRefreshInterval = 2 * time.Minute
+				c.NumWorkers = 64
+			},
+		},
+		{
+			name: "durations and names",
+			env: map[string]string{EnvTimeout: "1s", EnvRefreshInterval: "90s", EnvScanRange: "web, 5432",
+				EnvScanBackend: BackendLsof, EnvKillPolicy: KillPolicyForce},
+			modify: func(c *Config) {
+				c.PortTimeout = time.Second
+				c.AutoRefreshInterval = 90 * time.Second
+				c.Ports = "web, 5432"
+				c.ScanBackend = BackendLsof
+				c.KillPolicy = KillPolicyForce
+			},
+		},
+		{
+			name:       "every unparsable variable is reported and ignored",
+			env:        map[string]string{EnvScanRange: "0-5", EnvTimeout: "soon", EnvRefreshInterval: "later", EnvWorkers: "many"},
+			modify:     func(*Config) {},
+			wantFields: []string{EnvScanRange, EnvTimeout, EnvRefreshInterval, EnvWorkers},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, want := DefaultConfig(), DefaultConfig()
+			tt.modify(want)
+
+			var errs ValidationError
+			applyEnv(cfg, func(key string) string { return tt.env[key] }, &errs)
+
+			if !reflect.DeepEqual(cfg, want) {
+				t.Errorf("applyEnv() config = %+v, want %+v", cfg, want)
+			}
+			var fields []string
+			for _, field := range errs.Fields {
+				fields = append(fields, field.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("applyEnv() errors = %v, want %v", fields, tt.wantFields)
+			}
+		})
+	}
+}
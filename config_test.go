@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// setTestConfig makes a modified copy of the defaults the active config for
+// the rest of the test
+func setTestConfig(t *testing.T, modify func(*Config)) {
+	t.Helper()
+	previous := AppConfig()
+	t.Cleanup(func() { SetAppConfig(previous) })
+	cfg := DefaultConfig()
+	modify(cfg)
+	SetAppConfig(cfg)
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		modify     func(*Config)
+		wantFields []string // FieldError.Field of every problem, in order
+	}{
+		{
+			name:   "defaults",
+			modify: func(*Config) {},
+		},
+		{
+			name:       "invalid ports",
+			modify:     func(c *Config) { c.Ports = "3000-70000" },
+			wantFields: []string{"Ports"},
+		},
+		{
+			name:       "workers out of range",
+			modify:     func(c *Config) { c.NumWorkers = 0 },
+			wantFields: []string{"NumWorkers"},
+		},
+		{
+			name:       "unknown backend",
+			modify:     func(c *Config) { c.ScanBackend = "nmap" },
+			wantFields: []string{"ScanBackend"},
+		},
+		{
+			name:       "refresh too short",
+			modify:     func(c *Config) { c.AutoRefreshInterval = 5 * time.Second },
+			wantFields: []string{"AutoRefreshInterval"},
+		},
+		{
+			name:       "bad protection entries",
+			modify:     func(c *Config) { c.ProtectedPIDs = []int{1, -1}; c.ProtectedPaths = []string{"/usr/bin/["} },
+			wantFields: []string{"ProtectedPIDs", "ProtectedPaths"},
+		},
+		{
+			name: "every problem is reported",
+			modify: func(c *Config) {
+				c.Ports = ""
+				c.NumWorkers = 20000
+				c.PortTimeout = 0
+				c.KillPolicy = "nope"
+				c.WindowWidth = 100
+			},
+			wantFields: []string{"Ports", "NumWorkers", "PortTimeout", "KillPolicy", "WindowWidth"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() = %v, want a *ValidationError", err)
+			}
+			var fields []string
+			for _, field := range validationErr.Fields {
+				fields = append(fields, field.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.wantFields)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"github.com/fsnotify/fsnotify"
+)
+
+// configReloadDelay coalesces the burst of events an editor produces when
+// it saves a file (truncate, write, rename, chmod)
+const configReloadDelay = 250 * time.Millisecond
+
+// WatchConfig reloads the configuration whenever a config file is created,
+// changed or replaced, until quit is closed. Directories are watched rather
+// than files, so editors that save by renaming and files created after
+// startup are both picked up. A user config directory that does not exist
+// yet is watched for through its parent. Each reload layers files, saved
+// settings, environment and flags exactly like LoadConfig; a valid result
+// replaces AppConfig and onReload receives it, an invalid one leaves
+// AppConfig unchanged and onReload receives the error.
+func WatchConfig(flags *ConfigFlags, prefs fyne.Preferences, quit <-chan struct{}, onReload func(cfg *Config, files []string, err error)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	watching := 0
+	addErr := watcher.Add(".")
+	if addErr == nil {
+		watching++
+	}
+
+	// The user config directory may not exist yet; then its parent is
+	// watched until it is created
+	userDir, err := UserConfigDir()
+	pendingDir := ""
+	if err == nil {
+		if err := watcher.Add(userDir); err == nil {
+			watching++
+		} else if err := watcher.Add(filepath.Dir(userDir)); err == nil {
+			pendingDir = userDir
+			watching++
+		} else {
+			addErr = err
+		}
+	}
+	if watching == 0 {
+		watcher.Close()
+		return addErr
+	}
+
+	// Only the files ConfigFiles looks for count; other files with the same
+	// names, such as an unrelated ./config.yaml, do not
+	var candidates []string
+	if userDir != "" {
+		for _, name := range userConfigNames {
+			candidates = append(candidates, filepath.Join(userDir, name))
+		}
+	}
+	for _, name := range projectConfigNames {
+		candidates = append(candidates, filepath.Join(".", name))
+	}
+	isConfigFile := func(path string) bool {
+		return slices.Contains(candidates, filepath.Clean(path))
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		var reload <-chan time.Time
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if pendingDir != "" && event.Name == pendingDir && event.Op.Has(fsnotify.Create) {
+					// A config file may have been written before the watch
+					// was added, so reload either way
+					if err := watcher.Add(pendingDir); err == nil {
+						pendingDir = ""
+					}
+					reload = time.After(configReloadDelay)
+					continue
+				}
+				if isConfigFile(event.Name) && event.Op != fsnotify.Chmod {
+					reload = time.After(configReloadDelay)
+				}
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			case <-reload:
+				reload = nil
+				cfg, files, err := LoadConfig(flags, prefs)
+				if err == nil {
+					SetAppConfig(cfg)
+				}
+				onReload(cfg, files, err)
+			case <-quit:
+				return
+			}
+		}
+	}()
+	return nil
+}
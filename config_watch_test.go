@@ -0,0 +1,59 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchConfig(t *testing.T) {
+	userDir, workDir := isolateConfig(t)
+	setTestConfig(t, func(*Config) {})
+
+	reloads := make(chan *Config, 10)
+	quit := make(chan struct{})
+	t.Cleanup(func() { close(quit) })
+	err := WatchConfig(nil, nil, quit, func(cfg *Config, files []string, err error) {
+		if err != nil {
+			t.Errorf("reload error = %v", err)
+		}
+		reloads <- cfg
+	})
+	if err != nil {
+		t.Fatalf("WatchConfig() error = %v", err)
+	}
+
+	// Files that share a name with a config file but are not looked for
+	writeFile(t, filepath.Join(workDir, "config.yaml"), "num_workers: 7\n")
+	writeFile(t, filepath.Join(userDir, "devports.toml"), "num_workers = 8\n")
+	select {
+	case cfg := <-reloads:
+		t.Fatalf("reloaded with workers = %d after writing unrelated files", cfg.NumWorkers)
+	case <-time.After(4 * configReloadDelay):
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		content     string
+		wantWorkers int
+	}{
+		{name: "project file", path: filepath.Join(workDir, "devports.toml"), content: "num_workers = 20\n", wantWorkers: 20},
+		{name: "user file", path: filepath.Join(userDir, "config.toml"), content: "num_workers = 10\nports = \"web\"\n", wantWorkers: 20},
+		{name: "project file edited", path: filepath.Join(workDir, "devports.toml"), content: "ports = \"web\"\n", wantWorkers: 10},
+	}
+	for _, tt := range tests {
+		writeFile(t, tt.path, tt.content)
+		select {
+		case cfg := <-reloads:
+			if cfg.NumWorkers != tt.wantWorkers {
+				t.Errorf("%s: reloaded workers = %d, want %d", tt.name, cfg.NumWorkers, tt.wantWorkers)
+			}
+			if AppConfig() != cfg {
+				t.Errorf("%s: reloaded config is not active", tt.name)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s: no reload after writing %s", tt.name, tt.path)
+		}
+	}
+}
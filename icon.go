@@ -1,3 +1,5 @@
+//go:build !cli && !web
+
 package main
 
 import (
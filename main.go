@@ -1,8 +1,16 @@
+//go:build !cli && !web
+
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"image/color"
+	"os"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -23,35 +31,109 @@ type DevPortsApp struct {
 	myWindow       fyne.Window
 	table          *widget.Table
 	refreshBtn     *widget.Button
+	stopBtn        *widget.Button
+	historyBtn     *widget.Button
+	settingsBtn    *widget.Button
+	restartBtn     *widget.Button
+	progressBar    *widget.ProgressBar
 	statusLbl      *widget.Label
+	protocolSel    *widget.Select
+	portsSel       *widget.Select
+	detailsLbl     *widget.Label
+	treeLbl        *widget.Label
+	killTreeBtn    *widget.Button
+	killRootBtn    *widget.Button
+	freePortBtn    *widget.Button
+	infoText       *canvas.Text
+	detailsSeq     atomic.Uint64 // drops details of rows that are no longer selected
+	scanner        Scanner
+	scannerMu      sync.Mutex // protects scanner, replaced on config reload
 	ports          []PortInfo
-	portsMu        sync.RWMutex  // protects ports slice
+	visible        []PortInfo       // ports after the protocol filter, shown in the table
+	protocolFilter string           // "All", ProtoTCP or ProtoUDP
+	protection     *ProtectionIndex // protected processes as of the last scan; nil until loaded
+	portsMu        sync.RWMutex     // protects ports, visible, protocolFilter and protection
+	cancelScan     context.CancelFunc
+	cancelMu       sync.Mutex // protects cancelScan
 	isScanning     atomic.Bool
 	pendingRefresh atomic.Bool   // prevents multiple refresh goroutines
+	refreshReset   chan struct{} // reschedules auto-refresh after a config reload
+	flags          *ConfigFlags  // command-line flags, reapplied whenever the config is rebuilt
 	quit           chan struct{}
 }
 
 func main() {
-	// Validate configuration at startup
-	if err := AppConfig.Validate(); err != nil {
-		panic(fmt.Sprintf("Invalid configuration: %v", err))
+	flags, err := ParseFlags(os.Args[1:], os.Stderr)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(ExitOK)
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(ExitUsage)
+	}
+
+	// --web and --no-gui serve or run a command without opening a window
+	if flags.Web {
+		os.Exit(RunWeb(flags, os.Stdout, os.Stderr))
+	}
+	if flags.NoGUI {
+		os.Exit(RunCLI(flags, os.Stdout, os.Stderr))
+	}
+	if len(flags.Args) > 0 {
+		fmt.Fprintf(os.Stderr, "unexpected arguments: %s (use --no-gui to run a command)\n", strings.Join(flags.Args, " "))
+		os.Exit(ExitUsage)
 	}
 
-	myApp := app.New()
+	// The app ID gives the Settings dialog a persistent preferences store
+	myApp := app.NewWithID("io.github.screamm.devports-pro")
 	myApp.SetIcon(appIcon)
 
+	// Layer config files, saved settings, environment and flags over the defaults
+	cfg, files, configErr := LoadConfig(flags, myApp.Preferences())
+	if configErr != nil {
+		if flags.PrintConfig {
+			fmt.Fprintln(os.Stderr, configErr)
+			os.Exit(ExitUsage)
+		}
+		// Start with the defaults and list the problems once the window is up
+		cfg = DefaultConfig()
+	}
+	SetAppConfig(cfg)
+
+	if flags.PrintConfig {
+		if err := PrintConfig(os.Stdout, cfg, files); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(ExitFailure)
+		}
+		return
+	}
+
+	// The config is validated or the defaults, so the backend is known;
+	// should it not be, detect one rather than refuse to start
+	scanner, err := NewScanner(AppConfig().ScanBackend)
+	if err != nil {
+		scanner = detectScanner()
+	}
+
 	myWindow := myApp.NewWindow("⚡ DevPorts Pro - Port Scanner")
-	myWindow.Resize(fyne.NewSize(AppConfig.WindowWidth, AppConfig.WindowHeight))
+	myWindow.Resize(fyne.NewSize(AppConfig().WindowWidth, AppConfig().WindowHeight))
 	myWindow.CenterOnScreen()
 
 	devApp := &DevPortsApp{
-		myApp:    myApp,
-		myWindow: myWindow,
-		ports:    make([]PortInfo, 0),
-		quit:     make(chan struct{}),
+		myApp:        myApp,
+		myWindow:     myWindow,
+		scanner:      scanner,
+		ports:        make([]PortInfo, 0),
+		visible:      make([]PortInfo, 0),
+		quit:         make(chan struct{}),
+		refreshReset: make(chan struct{}, 1),
+		flags:        flags,
 	}
 
 	devApp.buildUI(myWindow)
+	if configErr != nil {
+		devApp.showConfigProblems(configErr)
+	}
 
 	// Start initial scan
 	go devApp.scanPorts()
@@ -59,8 +141,14 @@ func main() {
 	// Start auto-refresh timer (5 minutes)
 	go devApp.startAutoRefresh()
 
+	// Pick up edits to the config files while running
+	if err := WatchConfig(flags, myApp.Preferences(), devApp.quit, devApp.applyConfig); err != nil {
+		devApp.statusLbl.SetText(fmt.Sprintf("⚠ Config files are not watched for changes: %v", err))
+	}
+
 	// Graceful shutdown handler
 	myWindow.SetOnClosed(func() {
+		devApp.stopScan()
 		close(devApp.quit)
 	})
 
@@ -98,13 +186,57 @@ func (da *DevPortsApp) buildUI(w fyne.Window) {
 	})
 	da.refreshBtn.Importance = widget.HighImportance
 
+	// Stop button cancels a running scan, keeping the ports found so far
+	da.stopBtn = widget.NewButton("■ Stop", func() {
+		da.stopScan()
+	})
+	da.stopBtn.Disable()
+
+	// History browses the kill audit log
+	da.historyBtn = widget.NewButton("☰ History", func() {
+		da.showHistory()
+	})
+
+	// Settings edits the common options and saves them in the app preferences
+	da.settingsBtn = widget.NewButton("⚙ Settings", func() {
+		da.showSettings()
+	})
+
+	// Restart relaunches processes killed this session; shown once there are any
+	da.restartBtn = widget.NewButton("⟲ Restart...", func() {
+		da.showRestartDialog()
+	})
+	da.restartBtn.Hide()
+
+	// Protocol filter shows TCP and UDP side by side or one at a time
+	da.protocolSel = widget.NewSelect([]string{protocolAll, ProtoTCP, ProtoUDP}, func(selected string) {
+		da.setProtocolFilter(selected)
+	})
+	da.protocolSel.SetSelected(protocolAll)
+
+	// Ports picks a named preset; custom lists are edited in Settings
+	presetNames := make([]string, len(PortPresets))
+	for i, preset := range PortPresets {
+		presetNames[i] = preset.Name
+	}
+	da.portsSel = widget.NewSelect(presetNames, nil)
+	da.portsSel.PlaceHolder = "Custom ports"
+	da.updatePortsSelect()
+	da.portsSel.OnChanged = func(name string) {
+		da.selectPortPreset(name)
+	}
+
+	// Progress bar tracks ports probed during a scan
+	da.progressBar = widget.NewProgressBar()
+	da.progressBar.Hide()
+
 	// Create table with compact rows
 	da.table = widget.NewTable(
 		func() (int, int) {
 			da.portsMu.RLock()
-			count := len(da.ports)
+			count := len(da.visible)
 			da.portsMu.RUnlock()
-			return count + 1, 4 // +1 for header
+			return count + 1, 7 // +1 for header
 		},
 		func() fyne.CanvasObject {
 			label := widget.NewLabel("template")
@@ -124,10 +256,16 @@ func (da *DevPortsApp) buildUI(w fyne.Window) {
 				case 0:
 					text = "Port"
 				case 1:
-					text = "PID"
+					text = "Proto"
 				case 2:
-					text = "Process"
+					text = "Address"
 				case 3:
+					text = "Scope"
+				case 4:
+					text = "PID"
+				case 5:
+					text = "Process"
+				case 6:
 					text = "Action"
 				}
 				label := widget.NewLabel(text)
@@ -135,26 +273,50 @@ func (da *DevPortsApp) buildUI(w fyne.Window) {
 				cell.Objects = []fyne.CanvasObject{label}
 			} else {
 				da.portsMu.RLock()
-				if i.Row-1 < len(da.ports) {
-					port := da.ports[i.Row-1]
+				if i.Row-1 < len(da.visible) {
+					port := da.visible[i.Row-1]
 					da.portsMu.RUnlock()
 					switch i.Col {
 					case 0:
 						label := widget.NewLabel(fmt.Sprintf("%d", port.Port))
 						cell.Objects = []fyne.CanvasObject{label}
 					case 1:
-						label := widget.NewLabel(port.PID)
+						label := widget.NewLabel(port.Protocol)
 						cell.Objects = []fyne.CanvasObject{label}
 					case 2:
-						label := widget.NewLabel(port.Process)
+						label := widget.NewLabel(port.LocalAddress)
 						cell.Objects = []fyne.CanvasObject{label}
 					case 3:
+						// Flag services reachable from other machines
+						if port.Exposed() {
+							label := widget.NewLabel("⚠ Exposed")
+							label.Importance = widget.WarningImportance
+							cell.Objects = []fyne.CanvasObject{label}
+						} else {
+							label := widget.NewLabel("Loopback")
+							cell.Objects = []fyne.CanvasObject{label}
+						}
+					case 4:
+						label := widget.NewLabel(port.PID)
+						cell.Objects = []fyne.CanvasObject{label}
+					case 5:
+						label := widget.NewLabel(port.Process)
+						cell.Objects = []fyne.CanvasObject{label}
+					case 6:
 						if port.PID != "Unknown" && port.PID != "" && port.PID != "Timeout" {
 							// Capture values in local variables BEFORE the closure
 							pid := port.PID
 							portNum := port.Port
 							processName := port.Process
 
+							if da.protectionReason(pid) != "" {
+								// The reason is shown in the details pane
+								lockedBtn := widget.NewButton("🔒 Protected", nil)
+								lockedBtn.Disable()
+								cell.Objects = []fyne.CanvasObject{lockedBtn}
+								return
+							}
+
 							killBtn := widget.NewButton("⨯ Kill", func() {
 								da.showKillConfirmation(pid, portNum, processName)
 							})
@@ -176,27 +338,79 @@ func (da *DevPortsApp) buildUI(w fyne.Window) {
 		})
 
 	// Set optimized column widths for 1100px window
-	da.table.SetColumnWidth(0, 120) // Port
-	da.table.SetColumnWidth(1, 120) // PID
-	da.table.SetColumnWidth(2, 550) // Process
-	da.table.SetColumnWidth(3, 120) // Action
+	da.table.SetColumnWidth(0, 90)  // Port
+	da.table.SetColumnWidth(1, 70)  // Proto
+	da.table.SetColumnWidth(2, 180) // Address
+	da.table.SetColumnWidth(3, 120) // Scope
+	da.table.SetColumnWidth(4, 90)  // PID
+	da.table.SetColumnWidth(5, 300) // Process
+	da.table.SetColumnWidth(6, 120) // Action
+
+	// Selecting a row shows the owning process in the details pane
+	da.table.OnSelected = func(id widget.TableCellID) {
+		if id.Row == 0 {
+			return
+		}
+		da.portsMu.RLock()
+		if id.Row-1 >= len(da.visible) {
+			da.portsMu.RUnlock()
+			return
+		}
+		port := da.visible[id.Row-1]
+		da.portsMu.RUnlock()
+		go da.showDetails(port)
+	}
+
+	// Details pane for the selected row
+	da.detailsLbl = widget.NewLabel("Select a port to see its process details")
+	da.detailsLbl.TextStyle.Monospace = true
+	da.detailsLbl.Wrapping = fyne.TextWrapBreak
+	da.treeLbl = widget.NewLabel("")
+	da.treeLbl.TextStyle.Monospace = true
+
+	// Tree kill buttons are configured for the selected process in showDetails
+	da.killTreeBtn = widget.NewButton("⨯ Kill Tree", nil)
+	da.killTreeBtn.Importance = widget.DangerImportance
+	da.killTreeBtn.Hide()
+	da.killRootBtn = widget.NewButton("⨯ Kill From Root", nil)
+	da.killRootBtn.Importance = widget.DangerImportance
+	da.killRootBtn.Hide()
+	da.freePortBtn = widget.NewButton("⨯ Free Port", nil)
+	da.freePortBtn.Importance = widget.DangerImportance
+	da.freePortBtn.Hide()
+
+	detailsCard := widget.NewCard("", "▸ Process Details", container.NewBorder(
+		nil,
+		container.NewHBox(da.freePortBtn, da.killTreeBtn, da.killRootBtn),
+		nil,
+		nil,
+		container.NewVScroll(container.NewVBox(da.detailsLbl, da.treeLbl)),
+	))
+
+	split := container.NewVSplit(da.table, detailsCard)
+	split.Offset = 0.7
 
 	// Info banner
-	infoText := canvas.NewText(
-		fmt.Sprintf("▸ Scanning ports %d-%d | Auto-refresh: %v | Click [Kill] to terminate process",
-			AppConfig.PortRangeStart,
-			AppConfig.PortRangeEnd,
-			AppConfig.AutoRefreshInterval),
-		color.RGBA{120, 120, 120, 255})
-	infoText.TextStyle.Monospace = true
-	infoText.Alignment = fyne.TextAlignCenter
-	infoText.TextSize = 11
+	da.infoText = canvas.NewText("", color.RGBA{120, 120, 120, 255})
+	da.infoText.TextStyle.Monospace = true
+	da.infoText.Alignment = fyne.TextAlignCenter
+	da.infoText.TextSize = 11
+	da.updateInfoText()
 
 	// Top controls with terminal style
-	topContainer := container.NewHBox(
-		da.refreshBtn,
-		widget.NewSeparator(),
-		da.statusLbl,
+	topContainer := container.NewVBox(
+		container.NewHBox(
+			da.refreshBtn,
+			da.stopBtn,
+			da.protocolSel,
+			da.portsSel,
+			da.historyBtn,
+			da.settingsBtn,
+			da.restartBtn,
+			widget.NewSeparator(),
+			da.statusLbl,
+		),
+		da.progressBar,
 	)
 
 	// Header container
@@ -205,7 +419,7 @@ func (da *DevPortsApp) buildUI(w fyne.Window) {
 		title,
 		subtitle,
 		widget.NewSeparator(),
-		infoText,
+		da.infoText,
 		widget.NewSeparator(),
 	)
 
@@ -223,7 +437,7 @@ func (da *DevPortsApp) buildUI(w fyne.Window) {
 		footerText, // bottom
 		nil,        // left
 		nil,        // right
-		da.table,   // center
+		split,      // center
 	)
 
 	w.SetContent(content)
@@ -235,23 +449,639 @@ func (da *DevPortsApp) scanPorts() {
 	}
 	defer da.isScanning.Store(false)
 
+	ctx, cancel := context.WithCancel(context.Background())
+	da.cancelMu.Lock()
+	da.cancelScan = cancel
+	da.cancelMu.Unlock()
+	defer func() {
+		da.cancelMu.Lock()
+		da.cancelScan = nil
+		da.cancelMu.Unlock()
+		cancel()
+	}()
+
+	da.scannerMu.Lock()
+	scanner := da.scanner
+	da.scannerMu.Unlock()
+
 	da.statusLbl.SetText("⟳ Scanning ports...")
 	da.refreshBtn.SetText("⏳ Scanning...")
 	da.refreshBtn.Disable()
+	da.stopBtn.Enable()
+	da.progressBar.SetValue(0)
+	da.progressBar.Show()
+
+	// Start from an empty table so partial results stream in
+	da.setPorts(make([]PortInfo, 0))
+
+	// Classify processes once so every row can show whether it is protected.
+	// Without a listing only PID rules apply; KillProcess checks again anyway.
+	if protection, err := LoadProtectionIndex(); err == nil {
+		da.portsMu.Lock()
+		da.protection = protection
+		da.portsMu.Unlock()
+	}
 
-	// Use optimized concurrent port scanner
+	type scanResult struct {
+		ports []PortInfo
+		err   error
+	}
+	progress := make(chan ScanProgress, 16)
+	resultCh := make(chan scanResult, 1)
+
+	// Use the selected scan backend
 	startTime := time.Now()
-	activePorts := ScanPorts()
+	go func() {
+		activePorts, err := ScanPorts(ctx, ScanOptions{Scanner: scanner, Progress: progress})
+		resultCh <- scanResult{activePorts, err}
+	}()
+
+	var found []PortInfo
+	for p := range progress {
+		if len(p.Found) > 0 {
+			found = append(found, p.Found...)
+			sortPorts(found)
+			da.setPorts(append([]PortInfo(nil), found...))
+		}
+		if p.Total > 0 {
+			da.progressBar.SetValue(float64(p.Probed) / float64(p.Total))
+		}
+		da.statusLbl.SetText(fmt.Sprintf("⟳ Scanning... %d/%d ports probed, %d found", p.Probed, p.Total, len(found)))
+	}
+
+	result := <-resultCh
 	elapsed := time.Since(startTime)
 
+	da.refreshBtn.SetText("⟳ Refresh Scan")
+	da.refreshBtn.Enable()
+	da.stopBtn.Disable()
+	da.progressBar.Hide()
+
+	if result.err != nil && ctx.Err() == nil {
+		da.statusLbl.SetText(fmt.Sprintf("✗ Scan failed (%s): %v", scanner.Name(), result.err))
+		return
+	}
+
+	if result.ports != nil {
+		da.setPorts(result.ports)
+	}
+
+	if ctx.Err() != nil {
+		da.statusLbl.SetText(fmt.Sprintf("■ Scan stopped: %d active ports found so far (%.2fs)", len(result.ports), elapsed.Seconds()))
+		return
+	}
+
+	da.statusLbl.SetText(fmt.Sprintf("✓ Scan complete: %d active ports found (%.2fs, %s)", len(result.ports), elapsed.Seconds(), scanner.Name()))
+}
+
+// showDetails fills the details pane with the process holding a port and
+// its place in the process tree
+func (da *DevPortsApp) showDetails(port PortInfo) {
+	seq := da.detailsSeq.Add(1)
+	current := func() bool { return da.detailsSeq.Load() == seq }
+
+	header := fmt.Sprintf("Port:     %d/%s on %s (%s)\n", port.Port, port.Protocol, port.LocalAddress, port.Family)
+	da.treeLbl.SetText("")
+	da.killTreeBtn.Hide()
+	da.killRootBtn.Hide()
+	da.freePortBtn.Hide()
+
+	if port.PID == "Unknown" || port.PID == "" || port.PID == "Timeout" {
+		da.detailsLbl.SetText(header + "No process information available for this port")
+		return
+	}
+
+	if reason := da.protectionReason(port.PID); reason != "" {
+		header += fmt.Sprintf("🔒 %s\n", reason)
+	} else {
+		// Freeing the port kills every holder, not just the listed PID
+		da.freePortBtn.SetText(fmt.Sprintf("⨯ Free Port %d", port.Port))
+		da.freePortBtn.OnTapped = func() {
+			da.showFreePortConfirmation(port.Port)
+		}
+		da.freePortBtn.Show()
+	}
+
+	da.detailsLbl.SetText(header + fmt.Sprintf("⏳ Loading details for PID %s...", port.PID))
+	details, err := GetProcessDetails(port.PID)
+	if !current() {
+		return
+	}
+	if err != nil {
+		da.detailsLbl.SetText(header + fmt.Sprintf("✗ %v", err))
+		return
+	}
+	da.detailsLbl.SetText(header + details.String())
+
+	tree, err := LoadProcessTree()
+	if !current() {
+		return
+	}
+	if err != nil {
+		da.treeLbl.SetText(fmt.Sprintf("✗ Process tree unavailable: %v", err))
+		return
+	}
+	da.treeLbl.SetText("\nProcess tree:\n" + tree.Render(details.PID))
+
+	// Killing the subtree also stops workers the process spawned
+	subtree := tree.Subtree(details.PID)
+	if len(subtree) > 1 {
+		da.killTreeBtn.SetText(fmt.Sprintf("⨯ Kill Tree (%d processes)", len(subtree)))
+		da.killTreeBtn.OnTapped = func() {
+			da.showTreeKillConfirmation(subtree)
+		}
+		da.killTreeBtn.Show()
+	}
+
+	// Killing the session root stops supervisors that would respawn it
+	if root := tree.SessionRoot(details.PID); root != nil && root.PID != details.PID {
+		rootTree := tree.Subtree(root.PID)
+		da.killRootBtn.SetText(fmt.Sprintf("⨯ Kill From %s (%d)", root.Name, root.PID))
+		da.killRootBtn.OnTapped = func() {
+			da.showTreeKillConfirmation(rootTree)
+		}
+		da.killRootBtn.Show()
+	}
+}
+
+// showTreeKillConfirmation asks before terminating a process and everything
+// below it. nodes[0] is the top of the subtree.
+func (da *DevPortsApp) showTreeKillConfirmation(nodes []*ProcessNode) {
+	if len(nodes) == 0 {
+		return
+	}
+
+	var list strings.Builder
+	for i, n := range nodes {
+		if i == 10 {
+			fmt.Fprintf(&list, "  ... and %d more\n", len(nodes)-i)
+			break
+		}
+		fmt.Fprintf(&list, "  %s (PID %d)\n", n.Name, n.PID)
+	}
+
+	message := fmt.Sprintf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n"+
+		"This will terminate %d processes:\n\n%s\n"+
+		"Are you sure you want to terminate this process tree?\n\n"+
+		"━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━", len(nodes), list.String())
+
+	rootPID := strconv.Itoa(nodes[0].PID)
+	da.showKillPolicyConfirm("⚠️  Terminate Process Tree", message, func(policy string) {
+		go da.executeTreeKill(rootPID, policy)
+	})
+}
+
+func (da *DevPortsApp) executeTreeKill(pid, policy string) {
+	da.statusLbl.SetText(fmt.Sprintf("⏳ Terminating process tree of PID %s...", pid))
+
+	err := KillProcessTree(pid, policy)
+	if err != nil {
+		da.statusLbl.SetText(fmt.Sprintf("✗ Failed to kill tree of PID %s", pid))
+		dialog.ShowError(fmt.Errorf("process tree termination failed: %v", err), da.myWindow)
+	} else {
+		da.statusLbl.SetText(fmt.Sprintf("✓ Process tree of PID %s terminated successfully", pid))
+	}
+
+	da.scheduleRefresh()
+}
+
+// showFreePortConfirmation asks before terminating every process holding a port
+func (da *DevPortsApp) showFreePortConfirmation(port int) {
+	message := fmt.Sprintf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n"+
+		"This will terminate every process holding port %d\n"+
+		"and check that the port is released.\n\n"+
+		"Are you sure you want to free this port?\n\n"+
+		"━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━", port)
+
+	da.showKillPolicyConfirm("⚠️  Free Port", message, func(policy string) {
+		go da.executeKillPort(port, policy)
+	})
+}
+
+func (da *DevPortsApp) executeKillPort(port int, policy string) {
+	da.statusLbl.SetText(fmt.Sprintf("⏳ Freeing port %d...", port))
+
+	err := KillPortWithPolicy(port, policy)
+	if err != nil {
+		da.statusLbl.SetText(fmt.Sprintf("✗ Failed to free port %d", port))
+		dialog.ShowError(fmt.Errorf("freeing port failed: %v", err), da.myWindow)
+	} else {
+		da.statusLbl.SetText(fmt.Sprintf("✓ Port %d is free", port))
+	}
+
+	da.scheduleRefresh()
+}
+
+// showHistory lists the kill audit log, newest first, with the command line
+// and error of the selected entry below the table
+func (da *DevPortsApp) showHistory() {
+	entries, err := ReadAuditLog()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("failed to read audit log: %v", err), da.myWindow)
+		return
+	}
+	if len(entries) == 0 {
+		dialog.ShowInformation("Kill History", "No processes have been terminated yet.", da.myWindow)
+		return
+	}
+
+	headers := []string{"Time", "User", "PID", "Ports", "Process", "Signal", "Outcome"}
+	cellText := func(e AuditEntry, col int) string {
+		switch col {
+		case 0:
+			return e.Time.Local().Format("2006-01-02 15:04:05")
+		case 1:
+			return e.User
+		case 2:
+			return strconv.Itoa(e.PID)
+		case 3:
+			ports := make([]string, len(e.Ports))
+			for i, p := range e.Ports {
+				ports[i] = strconv.Itoa(p)
+			}
+			return strings.Join(ports, ", ")
+		case 4:
+			return e.Process
+		case 5:
+			if e.Elevated {
+				return e.Signal + " (elevated)"
+			}
+			return e.Signal
+		case 6:
+			return e.Outcome
+		}
+		return ""
+	}
+
+	detailsLbl := widget.NewLabel("Select an entry to see its command line")
+	detailsLbl.TextStyle.Monospace = true
+	detailsLbl.Wrapping = fyne.TextWrapBreak
+
+	table := widget.NewTable(
+		func() (int, int) { return len(entries) + 1, len(headers) },
+		func() fyne.CanvasObject { return widget.NewLabel("template") },
+		func(id widget.TableCellID, o fyne.CanvasObject) {
+			label, ok := o.(*widget.Label)
+			if !ok {
+				return
+			}
+			label.Importance = widget.MediumImportance
+			if id.Row == 0 {
+				label.TextStyle.Bold = true
+				label.SetText(headers[id.Col])
+				return
+			}
+			e := entries[id.Row-1]
+			label.TextStyle.Bold = false
+			if id.Col == 6 && e.Outcome != AuditTerminated {
+				label.Importance = widget.WarningImportance
+			}
+			label.SetText(cellText(e, id.Col))
+		})
+	for col, width := range []float32{170, 100, 70, 100, 160, 150, 100} {
+		table.SetColumnWidth(col, width)
+	}
+	table.OnSelected = func(id widget.TableCellID) {
+		if id.Row == 0 {
+			return
+		}
+		e := entries[id.Row-1]
+		text := "Command:  —"
+		if e.CommandLine != "" {
+			text = "Command:  " + e.CommandLine
+		}
+		if e.Error != "" {
+			text += "\nError:    " + e.Error
+		}
+		detailsLbl.SetText(text)
+	}
+
+	content := container.NewBorder(
+		widget.NewLabel(fmt.Sprintf("%d kill attempts recorded in %s", len(entries), AppConfig().AuditLogPath)),
+		container.NewVScroll(detailsLbl),
+		nil,
+		nil,
+		table,
+	)
+	history := dialog.NewCustom("☰ Kill History", "Close", content, da.myWindow)
+	history.Resize(fyne.NewSize(AppConfig().WindowWidth*0.85, AppConfig().WindowHeight*0.75))
+	history.Show()
+}
+
+// updateRestartButton shows the Restart button while killed processes can
+// be restarted
+func (da *DevPortsApp) updateRestartButton() {
+	if n := len(KilledProcesses()); n > 0 {
+		da.restartBtn.SetText(fmt.Sprintf("⟲ Restart... (%d)", n))
+		da.restartBtn.Show()
+	} else {
+		da.restartBtn.Hide()
+	}
+}
+
+// showRestartDialog picks a process killed this session and relaunches it
+// with its original command line, optionally on another port
+func (da *DevPortsApp) showRestartDialog() {
+	specs := KilledProcesses()
+	if len(specs) == 0 {
+		da.updateRestartButton()
+		return
+	}
+
+	options := make([]string, len(specs))
+	for i, spec := range specs {
+		options[i] = fmt.Sprintf("%s (PID %d) at %s", spec.Name, spec.PID, spec.KilledAt.Format("15:04:05"))
+	}
+
+	commandLbl := widget.NewLabel("")
+	commandLbl.TextStyle.Monospace = true
+	commandLbl.Wrapping = fyne.TextWrapBreak
+	portEntry := widget.NewEntry()
+	portEntry.SetPlaceHolder("keep original")
+
+	selected := specs[0]
+	processSel := widget.NewSelect(options, func(choice string) {
+		for i, option := range options {
+			if option == choice {
+				selected = specs[i]
+			}
+		}
+		text := fmt.Sprintf("Command: %s", selected.CommandLine())
+		if selected.Cwd != "" {
+			text += fmt.Sprintf("\nCwd:     %s", selected.Cwd)
+		}
+		if len(selected.Ports) > 0 {
+			text += fmt.Sprintf("\nPorts:   %v", selected.Ports)
+		}
+		commandLbl.SetText(text)
+	})
+	processSel.SetSelected(options[0])
+
+	content := container.NewVBox(
+		processSel,
+		commandLbl,
+		widget.NewForm(widget.NewFormItem("PORT override", portEntry)),
+	)
+	restart := dialog.NewCustomConfirm("⟲ Restart Process", "Restart", "Cancel", content, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		port := 0
+		if text := strings.TrimSpace(portEntry.Text); text != "" {
+			var err error
+			if port, err = strconv.Atoi(text); err != nil || port < 1 || port > 65535 {
+				dialog.ShowError(fmt.Errorf("invalid port: %q (must be 1-65535)", text), da.myWindow)
+				return
+			}
+		}
+
+		newPID, err := RestartProcess(selected.PID, port)
+		da.updateRestartButton()
+		if err != nil {
+			da.statusLbl.SetText(fmt.Sprintf("✗ Failed to restart %s", selected.Name))
+			dialog.ShowError(err, da.myWindow)
+			return
+		}
+		da.statusLbl.SetText(fmt.Sprintf("✓ Restarted %s as PID %d", selected.Name, newPID))
+		da.scheduleRefresh()
+	}, da.myWindow)
+	restart.Resize(fyne.NewSize(AppConfig().WindowWidth*0.6, 0))
+	restart.Show()
+}
+
+// showSettings edits the common options with live validation. Saved values
+// go to the app preferences and take effect immediately.
+func (da *DevPortsApp) showSettings() {
+	current := AppConfig()
+	prefs := da.myApp.Preferences()
+
+	portsEntry := widget.NewEntry()
+	portsEntry.SetText(current.Ports)
+	portsEntry.SetPlaceHolder("e.g. 1-1024, 3000-3999, 5432, web")
+	workersEntry := widget.NewEntry()
+	workersEntry.SetText(strconv.Itoa(current.NumWorkers))
+	portTimeoutEntry := widget.NewEntry()
+	portTimeoutEntry.SetText(current.PortTimeout.String())
+	commandTimeoutEntry := widget.NewEntry()
+	commandTimeoutEntry.SetText(current.CommandTimeout.String())
+	refreshEntry := widget.NewEntry()
+	refreshEntry.SetText(current.AutoRefreshInterval.String())
+	policySel := widget.NewSelect(KillPolicies, nil)
+	policySel.SetSelected(current.KillPolicy)
+	widthEntry := widget.NewEntry()
+	widthEntry.SetText(strconv.FormatFloat(float64(current.WindowWidth), 'f', -1, 32))
+	heightEntry := widget.NewEntry()
+	heightEntry.SetText(strconv.FormatFloat(float64(current.WindowHeight), 'f', -1, 32))
+
+	errorLbl := widget.NewLabel("")
+	errorLbl.Wrapping = fyne.TextWrapWord
+	errorLbl.Importance = widget.DangerImportance
+	noteLbl := widget.NewLabel("Command-line flags and DEVPORTS_* variables still override these settings.")
+	noteLbl.Wrapping = fyne.TextWrapWord
+	noteLbl.Importance = widget.LowImportance
+
+	var saveBtn *widget.Button
+
+	// edited builds the candidate config from the form, or explains the
+	// first field that does not parse or validate
+	edited := func() (*Config, error) {
+		cfg := *current
+		var err error
+		cfg.Ports = strings.TrimSpace(portsEntry.Text)
+		if cfg.NumWorkers, err = strconv.Atoi(strings.TrimSpace(workersEntry.Text)); err != nil {
+			return nil, fmt.Errorf("workers must be a number")
+		}
+		if cfg.PortTimeout, err = time.ParseDuration(strings.TrimSpace(portTimeoutEntry.Text)); err != nil {
+			return nil, fmt.Errorf("port timeout must be a duration such as 100ms")
+		}
+		if cfg.CommandTimeout, err = time.ParseDuration(strings.TrimSpace(commandTimeoutEntry.Text)); err != nil {
+			return nil, fmt.Errorf("command timeout must be a duration such as 5s")
+		}
+		if cfg.AutoRefreshInterval, err = time.ParseDuration(strings.TrimSpace(refreshEntry.Text)); err != nil {
+			return nil, fmt.Errorf("auto-refresh interval must be a duration such as 5m")
+		}
+		cfg.KillPolicy = policySel.Selected
+		width, err := strconv.ParseFloat(strings.TrimSpace(widthEntry.Text), 32)
+		if err != nil {
+			return nil, fmt.Errorf("window width must be a number")
+		}
+		height, err := strconv.ParseFloat(strings.TrimSpace(heightEntry.Text), 32)
+		if err != nil {
+			return nil, fmt.Errorf("window height must be a number")
+		}
+		cfg.WindowWidth, cfg.WindowHeight = float32(width), float32(height)
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+		return &cfg, nil
+	}
+
+	validate := func() {
+		if _, err := edited(); err != nil {
+			errorLbl.SetText("✗ " + err.Error())
+			saveBtn.Disable()
+			return
+		}
+		errorLbl.SetText("")
+		saveBtn.Enable()
+	}
+	for _, entry := range []*widget.Entry{portsEntry, workersEntry, portTimeoutEntry,
+		commandTimeoutEntry, refreshEntry, widthEntry, heightEntry} {
+		entry.OnChanged = func(string) { validate() }
+	}
+	policySel.OnChanged = func(string) { validate() }
+
+	form := widget.NewForm(
+		widget.NewFormItem("Ports", portsEntry),
+		widget.NewFormItem("Workers", workersEntry),
+		widget.NewFormItem("Port timeout", portTimeoutEntry),
+		widget.NewFormItem("Command timeout", commandTimeoutEntry),
+		widget.NewFormItem("Auto-refresh", refreshEntry),
+		widget.NewFormItem("Kill policy", policySel),
+		widget.NewFormItem("Window width", widthEntry),
+		widget.NewFormItem("Window height", heightEntry),
+	)
+	settings := dialog.NewCustomWithoutButtons("⚙ Settings", container.NewVBox(form, errorLbl, noteLbl), da.myWindow)
+
+	reload := func(status string) {
+		if cfg, ok := da.reloadSettings(status); ok {
+			da.myWindow.Resize(fyne.NewSize(cfg.WindowWidth, cfg.WindowHeight))
+		}
+	}
+
+	saveBtn = widget.NewButtonWithIcon("Save", theme.ConfirmIcon(), func() {
+		cfg, err := edited()
+		if err != nil {
+			return
+		}
+		changed := ChangedSettings(current, cfg)
+		if len(changed) == 0 {
+			settings.Hide()
+			return
+		}
+		if err := SaveSettings(prefs, changed); err != nil {
+			dialog.ShowError(err, da.myWindow)
+			return
+		}
+		settings.Hide()
+		reload("⚙ Settings saved")
+	})
+	saveBtn.Importance = widget.HighImportance
+	resetBtn := widget.NewButton("Reset", func() {
+		dialog.ShowConfirm("Reset Settings", "Forget the saved settings and use config files and defaults?", func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			ClearSettings(prefs)
+			settings.Hide()
+			reload("⚙ Settings reset")
+		}, da.myWindow)
+	})
+	cancelBtn := widget.NewButtonWithIcon("Cancel", theme.CancelIcon(), func() {
+		settings.Hide()
+	})
+	settings.SetButtons([]fyne.CanvasObject{resetBtn, cancelBtn, saveBtn})
+
+	validate()
+	settings.Resize(fyne.NewSize(AppConfig().WindowWidth*0.5, 0))
+	settings.Show()
+}
+
+// reloadSettings rebuilds the config from every layer after the saved
+// settings changed, so flags and environment keep their precedence
+func (da *DevPortsApp) reloadSettings(status string) (*Config, bool) {
+	cfg, _, err := LoadConfig(da.flags, da.myApp.Preferences())
+	if err != nil {
+		da.statusLbl.SetText("✗ Settings saved but not applied")
+		dialog.ShowError(fmt.Errorf("the configuration is invalid and was not applied:\n\n%v", err), da.myWindow)
+		return nil, false
+	}
+	SetAppConfig(cfg)
+	da.useConfig(cfg)
+	da.statusLbl.SetText(status)
+	return cfg, true
+}
+
+// selectPortPreset saves a preset from the toolbar as the ports to scan
+// and rescans with it
+func (da *DevPortsApp) selectPortPreset(name string) {
+	if name == "" || strings.EqualFold(name, AppConfig().Ports) {
+		return
+	}
+	if err := SaveSettings(da.myApp.Preferences(), map[string]any{"ports": name}); err != nil {
+		dialog.ShowError(err, da.myWindow)
+		return
+	}
+	if _, ok := da.reloadSettings(fmt.Sprintf("⚙ Scanning the %s ports", name)); ok && !da.isScanning.Load() {
+		go da.scanPorts()
+	}
+}
+
+// updatePortsSelect shows the active preset, or a placeholder when the
+// ports are a custom list
+func (da *DevPortsApp) updatePortsSelect() {
+	if preset, ok := lookupPortPreset(AppConfig().Ports); ok {
+		da.portsSel.SetSelected(preset.Name)
+		return
+	}
+	da.portsSel.ClearSelected()
+}
+
+// protectionReason explains why pid may not be killed, or returns ""
+func (da *DevPortsApp) protectionReason(pid string) string {
+	da.portsMu.RLock()
+	protection := da.protection
+	da.portsMu.RUnlock()
+	if protection == nil {
+		protection = &ProtectionIndex{}
+	}
+	return protection.Reason(pid)
+}
+
+// protocolAll is the protocol filter choice that shows every port
+const protocolAll = "All"
+
+// setPorts replaces the scan results and refreshes the table
+func (da *DevPortsApp) setPorts(ports []PortInfo) {
 	da.portsMu.Lock()
-	da.ports = activePorts
+	da.ports = ports
+	da.visible = filterPorts(ports, da.protocolFilter)
 	da.portsMu.Unlock()
 	da.table.Refresh()
+}
 
-	da.statusLbl.SetText(fmt.Sprintf("✓ Scan complete: %d active ports found (%.2fs)", len(activePorts), elapsed.Seconds()))
-	da.refreshBtn.SetText("⟳ Refresh Scan")
-	da.refreshBtn.Enable()
+// setProtocolFilter limits the table to a single protocol, or all of them
+func (da *DevPortsApp) setProtocolFilter(protocol string) {
+	da.portsMu.Lock()
+	da.protocolFilter = protocol
+	da.visible = filterPorts(da.ports, protocol)
+	da.portsMu.Unlock()
+	if da.table != nil {
+		da.table.Refresh()
+	}
+}
+
+// filterPorts returns the ports matching protocol; "All" or "" keeps everything
+func filterPorts(ports []PortInfo, protocol string) []PortInfo {
+	if protocol == "" || protocol == protocolAll {
+		return ports
+	}
+	filtered := make([]PortInfo, 0, len(ports))
+	for _, p := range ports {
+		if p.Protocol == protocol {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+// stopScan cancels the running scan, if any
+func (da *DevPortsApp) stopScan() {
+	da.cancelMu.Lock()
+	defer da.cancelMu.Unlock()
+	if da.cancelScan != nil {
+		da.cancelScan()
+	}
 }
 
 func (da *DevPortsApp) showKillConfirmation(pid string, port int, process string) {
@@ -278,34 +1108,117 @@ func (da *DevPortsApp) showKillConfirmation(pid string, port int, process string
 			"━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━", pid, port)
 	}
 
-	dialog.ShowConfirm(
-		title,
-		message,
-		func(confirmed bool) {
-			if confirmed {
-				da.executeKill(pid)
-			}
-		},
-		da.myWindow,
+	da.showKillPolicyConfirm(title, message, func(policy string) {
+		go da.executeKill(pid, policy)
+	})
+}
+
+// Kill policy choices shown in the confirmation dialogs
+var killPolicyLabels = map[string]string{
+	KillPolicyGraceful: "Graceful (SIGTERM, then SIGKILL)",
+	KillPolicyForce:    "Force (SIGKILL)",
+}
+
+// showKillPolicyConfirm shows a confirmation dialog with a kill policy
+// selector preset to AppConfig().KillPolicy, and calls onConfirm with the
+// chosen policy
+func (da *DevPortsApp) showKillPolicyConfirm(title, message string, onConfirm func(policy string)) {
+	options := make([]string, 0, len(KillPolicies))
+	for _, policy := range KillPolicies {
+		options = append(options, killPolicyLabels[policy])
+	}
+	policyGroup := widget.NewRadioGroup(options, nil)
+	policyGroup.Required = true
+	policyGroup.SetSelected(killPolicyLabels[AppConfig().KillPolicy])
+
+	content := container.NewVBox(
+		widget.NewLabel(message),
+		widget.NewLabel(fmt.Sprintf("Termination (grace period %v):", AppConfig().KillGracePeriod)),
+		policyGroup,
 	)
+
+	dialog.ShowCustomConfirm(title, "Terminate", "Cancel", content, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		policy := AppConfig().KillPolicy
+		for p, label := range killPolicyLabels {
+			if label == policyGroup.Selected {
+				policy = p
+			}
+		}
+		onConfirm(policy)
+	}, da.myWindow)
 }
 
-func (da *DevPortsApp) executeKill(pid string) {
+func (da *DevPortsApp) executeKill(pid, policy string) {
 	da.statusLbl.SetText(fmt.Sprintf("⏳ Terminating process PID %s...", pid))
 
-	err := KillProcess(pid)
-	if err != nil {
+	err := KillProcessWithPolicy(pid, policy)
+	switch {
+	case err == nil:
+		da.statusLbl.SetText(fmt.Sprintf("✓ Process PID %s terminated successfully", pid))
+	case errors.Is(err, ErrNoSuchProcess):
+		// Nothing left to kill - the table is just stale
+		da.statusLbl.SetText(fmt.Sprintf("✓ Process PID %s had already exited", pid))
+	case errors.Is(err, ErrPermissionDenied):
+		da.statusLbl.SetText(fmt.Sprintf("✗ Not allowed to kill PID %s", pid))
+		da.offerElevatedKill(pid, policy, err)
+	case errors.Is(err, ErrProtectedProcess):
+		da.statusLbl.SetText(fmt.Sprintf("✗ PID %s is protected", pid))
+		dialog.ShowError(err, da.myWindow)
+	default:
 		da.statusLbl.SetText(fmt.Sprintf("✗ Failed to kill PID %s: %v", pid, err))
 		// Show error dialog for better user feedback
 		dialog.ShowError(fmt.Errorf("process termination failed: %v", err), da.myWindow)
-	} else {
-		da.statusLbl.SetText(fmt.Sprintf("✓ Process PID %s terminated successfully", pid))
 	}
 
 	// Always refresh after kill attempt to show current state
+	da.scheduleRefresh()
+}
+
+// offerElevatedKill explains why a kill was refused and lets the user retry
+// that one kill with administrator rights
+func (da *DevPortsApp) offerElevatedKill(pid, policy string, cause error) {
+	message := fmt.Sprintf("PID %s belongs to another user or is privileged,\n"+
+		"so DevPorts Pro is not allowed to terminate it.\n\n%v\n\n"+
+		"Retry with administrator rights? You will be asked for a password\n"+
+		"(pkexec or sudo on Linux and macOS, a UAC prompt on Windows).\n"+
+		"Only this kill runs elevated and it is recorded in the audit log.", pid, cause)
+
+	dialog.ShowConfirm("🔐 Permission Denied", message, func(confirmed bool) {
+		if confirmed {
+			go da.executeElevatedKill(pid, policy)
+		}
+	}, da.myWindow)
+}
+
+func (da *DevPortsApp) executeElevatedKill(pid, policy string) {
+	da.statusLbl.SetText(fmt.Sprintf("⏳ Terminating process PID %s with administrator rights...", pid))
+
+	err := KillProcessElevated(pid, policy)
+	switch {
+	case err == nil:
+		da.statusLbl.SetText(fmt.Sprintf("✓ Process PID %s terminated (elevated)", pid))
+	case errors.Is(err, ErrElevationCancelled):
+		da.statusLbl.SetText(fmt.Sprintf("■ Elevation cancelled, PID %s left running", pid))
+	case errors.Is(err, ErrNoSuchProcess):
+		da.statusLbl.SetText(fmt.Sprintf("✓ Process PID %s had already exited", pid))
+	default:
+		da.statusLbl.SetText(fmt.Sprintf("✗ Failed to kill PID %s", pid))
+		dialog.ShowError(fmt.Errorf("elevated process termination failed: %v", err), da.myWindow)
+	}
+
+	da.scheduleRefresh()
+}
+
+// scheduleRefresh rescans after PostKillRefreshDelay, coalescing requests.
+// Every kill path calls it, so it also offers the killed processes for restart.
+func (da *DevPortsApp) scheduleRefresh() {
+	da.updateRestartButton()
 	if da.pendingRefresh.CompareAndSwap(false, true) {
 		go func() {
-			time.Sleep(AppConfig.PostKillRefreshDelay)
+			time.Sleep(AppConfig().PostKillRefreshDelay)
 			da.pendingRefresh.Store(false)
 			if !da.isScanning.Load() {
 				da.scanPorts()
@@ -315,7 +1228,7 @@ func (da *DevPortsApp) executeKill(pid string) {
 }
 
 func (da *DevPortsApp) startAutoRefresh() {
-	ticker := time.NewTicker(AppConfig.AutoRefreshInterval)
+	ticker := time.NewTicker(AppConfig().AutoRefreshInterval)
 	defer ticker.Stop()
 
 	for {
@@ -324,8 +1237,79 @@ func (da *DevPortsApp) startAutoRefresh() {
 			if !da.isScanning.Load() {
 				go da.scanPorts()
 			}
+		case <-da.refreshReset:
+			ticker.Reset(AppConfig().AutoRefreshInterval)
 		case <-da.quit:
 			return // Graceful exit
 		}
 	}
 }
+
+// updateInfoText shows the active port range and refresh interval
+func (da *DevPortsApp) updateInfoText() {
+	cfg := AppConfig()
+	da.infoText.Text = fmt.Sprintf("▸ Scanning %s (%d ports) | Auto-refresh: %v | Click [Kill] to terminate process",
+		cfg.Ports, cfg.PortSet().Count(), cfg.AutoRefreshInterval)
+	da.infoText.Refresh()
+}
+
+// applyConfig takes over a reloaded configuration, which WatchConfig has
+// already made active, or reports why the edited file was rejected
+func (da *DevPortsApp) applyConfig(cfg *Config, files []string, err error) {
+	if err != nil {
+		da.statusLbl.SetText("✗ Config reload failed, keeping the previous configuration")
+		dialog.ShowError(fmt.Errorf("the edited configuration is invalid and was not applied:\n\n%v", err), da.myWindow)
+		return
+	}
+	da.useConfig(cfg)
+
+	if len(files) == 0 {
+		da.statusLbl.SetText("⚙ Config files removed, using defaults")
+		return
+	}
+	da.statusLbl.SetText(fmt.Sprintf("⚙ Configuration reloaded from %s", strings.Join(files, ", ")))
+}
+
+// showConfigProblems lists every reason the configuration was rejected at
+// startup, while the app carries on with the defaults
+func (da *DevPortsApp) showConfigProblems(err error) {
+	text := err.Error()
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		lines := make([]string, len(validationErr.Fields))
+		for i, field := range validationErr.Fields {
+			lines[i] = "• " + field.Error()
+		}
+		text = strings.Join(lines, "\n")
+	}
+
+	problemsLbl := widget.NewLabel(text)
+	problemsLbl.Wrapping = fyne.TextWrapWord
+	problemsLbl.TextStyle.Monospace = true
+	noteLbl := widget.NewLabel("DevPorts Pro is using the default configuration. Fix the config files, DEVPORTS_* variables or flags; edited config files are picked up without a restart.")
+	noteLbl.Wrapping = fyne.TextWrapWord
+
+	problems := dialog.NewCustom("⚠ Invalid Configuration", "OK",
+		container.NewBorder(nil, noteLbl, nil, nil, container.NewVScroll(problemsLbl)), da.myWindow)
+	problems.Resize(fyne.NewSize(AppConfig().WindowWidth*0.7, AppConfig().WindowHeight*0.5))
+	problems.Show()
+}
+
+// useConfig switches the scanner, banner and auto-refresh timer over to
+// cfg, which must already be active
+func (da *DevPortsApp) useConfig(cfg *Config) {
+	// Validate has already checked the backend name
+	if scanner, err := NewScanner(cfg.ScanBackend); err == nil {
+		da.scannerMu.Lock()
+		da.scanner = scanner
+		da.scannerMu.Unlock()
+	}
+	da.updateInfoText()
+	da.updatePortsSelect()
+
+	// Restart the auto-refresh timer with the new interval
+	select {
+	case da.refreshReset <- struct{}{}:
+	default:
+	}
+}
@@ -2,44 +2,137 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"net"
-	"os/exec"
-	"regexp"
-	"runtime"
-	"sort"
 	"strconv"
 	"strings"
 	"sync"
-	"syscall"
-	"time"
-)
-
-var (
-	netstatCache     string
-	netstatCacheMu   sync.RWMutex
-	netstatCacheTime time.Time
-	netstatCacheTTL  = 2 * time.Second
 )
 
 type PortInfo struct {
-	Port    int
-	PID     string
-	Process string
-	Status  string
+	Port         int    `json:"port"`
+	Protocol     string `json:"protocol"`      // ProtoTCP or ProtoUDP
+	LocalAddress string `json:"local_address"` // bind address, e.g. "127.0.0.1", "0.0.0.0" or "::1"
+	Family       string `json:"family"`        // FamilyIPv4 or FamilyIPv6
+	PID          string `json:"pid"`
+	Process      string `json:"process"`
+	Status       string `json:"status"`
+}
+
+// Exposed reports whether the socket accepts traffic from other machines,
+// i.e. it is bound to a wildcard or non-loopback address
+func (p PortInfo) Exposed() bool {
+	host := p.LocalAddress
+	if i := strings.IndexByte(host, '%'); i >= 0 {
+		host = host[:i] // drop IPv6 zone, e.g. fe80::1%eth0
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	return !ip.IsLoopback()
+}
+
+// ScanProgress reports how far a running scan has got
+type ScanProgress struct {
+	Probed int        // ports checked so far
+	Total  int        // ports to check in this scan
+	Found  []PortInfo // ports discovered since the previous event
+}
+
+// ScanOptions controls a single ScanPorts call
+type ScanOptions struct {
+	// Scanner is the backend to use; nil selects AppConfig().ScanBackend
+	Scanner Scanner
+	// Progress optionally receives progress events and is closed when the
+	// scan finishes
+	Progress chan<- ScanProgress
+}
+
+// progressScanner is implemented by backends that can report partial
+// results while they run
+type progressScanner interface {
+	scanWithProgress(ctx context.Context, report func(ScanProgress)) ([]PortInfo, error)
+}
+
+// progressInterval is how many probed ports pass between progress events
+const progressInterval = 250
+
+// ScanPorts runs a scan that stops early when ctx is cancelled. On
+// cancellation the ports found so far are returned along with ctx.Err().
+func ScanPorts(ctx context.Context, opts ScanOptions) ([]PortInfo, error) {
+	if opts.Progress != nil {
+		defer close(opts.Progress)
+	}
+
+	scanner := opts.Scanner
+	if scanner == nil {
+		var err error
+		scanner, err = NewScanner(AppConfig().ScanBackend)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	report := func(p ScanProgress) {
+		if opts.Progress == nil {
+			return
+		}
+		select {
+		case opts.Progress <- p:
+		case <-ctx.Done():
+		}
+	}
+
+	if ps, ok := scanner.(progressScanner); ok {
+		return ps.scanWithProgress(ctx, report)
+	}
+
+	// Snapshot backends finish in one step - report everything at the end
+	activePorts, err := scanner.Scan(ctx)
+	if err == nil {
+		total := AppConfig().PortSet().Count()
+		report(ScanProgress{Probed: total, Total: total, Found: activePorts})
+	}
+	return activePorts, err
+}
+
+// DialScanner probes every configured port with a TCP dial on
+// the loopback addresses and every local interface address, and resolves the
+// owning process for each open port. UDP cannot be probed this way, so only
+// TCP ports are reported.
+type DialScanner struct{}
+
+func (DialScanner) Name() string { return BackendDial }
+
+func (d DialScanner) Scan(ctx context.Context) ([]PortInfo, error) {
+	return d.scanWithProgress(ctx, func(ScanProgress) {})
 }
 
-func ScanPorts() []PortInfo {
+// probeResult is the outcome of probing a single port: one entry per bind
+// address that accepted, or none if the port is closed
+type probeResult struct {
+	infos []PortInfo
+}
+
+func (DialScanner) scanWithProgress(ctx context.Context, report func(ScanProgress)) ([]PortInfo, error) {
+	// One config for the whole scan, even if it is reloaded meanwhile
+	cfg := AppConfig()
+
 	var activePorts []PortInfo
-	var mutex sync.Mutex
+	ports := cfg.PortSet()
+	total := ports.Count()
+	targets := localProbeTargets()
+
+	// Resolve owners from one shared snapshot instead of a command per port
+	snapshot, snapshotErr := getProcessSnapshot(ctx)
 
 	// Use worker pool with channel for concurrent scanning
-	portChan := make(chan int, AppConfig.PortRangeEnd)
-	resultChan := make(chan PortInfo, 100)
+	portChan := make(chan int, total)
+	resultChan := make(chan probeResult, 100)
 	var wg sync.WaitGroup
 
 	// Start concurrent workers for fast scanning
-	numWorkers := AppConfig.NumWorkers
+	numWorkers := cfg.NumWorkers
 	for i := 0; i < numWorkers; i++ {
 		wg.Add(1)
 		go func() {
@@ -51,33 +144,61 @@ func ScanPorts() []PortInfo {
 				}
 			}()
 			for port := range portChan {
-				if isPortOpen(port) {
-					pid, process := getProcessInfo(port)
-					resultChan <- PortInfo{
-						Port:    port,
-						PID:     pid,
-						Process: process,
-						Status:  "Active",
+				// Drain remaining ports without probing once cancelled
+				if ctx.Err() != nil {
+					continue
+				}
+				binds := probePort(port, targets)
+				if len(binds) == 0 {
+					resultChan <- probeResult{}
+					continue
+				}
+
+				pid, process := "Unknown", "Unknown"
+				if snapshotErr == nil {
+					if owner, name, ok := snapshot.Lookup(ProtoTCP, port); ok {
+						pid, process = owner, name
 					}
 				}
+				infos := make([]PortInfo, 0, len(binds))
+				for _, bind := range binds {
+					infos = append(infos, PortInfo{
+						Port:         port,
+						Protocol:     ProtoTCP,
+						LocalAddress: bind.Address,
+						Family:       bind.Family,
+						PID:          pid,
+						Process:      process,
+						Status:       "Active",
+					})
+				}
+				resultChan <- probeResult{infos: infos}
 			}
 		}()
 	}
 
-	// Result collector goroutine
+	// Result collector goroutine - the only writer of activePorts
 	done := make(chan bool)
 	go func() {
-		for portInfo := range resultChan {
-			mutex.Lock()
-			activePorts = append(activePorts, portInfo)
-			mutex.Unlock()
+		probed := 0
+		var found []PortInfo
+		for result := range resultChan {
+			probed++
+			activePorts = append(activePorts, result.infos...)
+			found = append(found, result.infos...)
+			if len(found) > 0 || probed%progressInterval == 0 || probed == total {
+				report(ScanProgress{Probed: probed, Total: total, Found: found})
+				found = nil
+			}
 		}
 		done <- true
 	}()
 
 	// Send ports to workers
-	for port := AppConfig.PortRangeStart; port <= AppConfig.PortRangeEnd; port++ {
-		portChan <- port
+	for _, r := range ports {
+		for port := r.Start; port <= r.End; port++ {
+			portChan <- port
+		}
 	}
 	close(portChan)
 
@@ -87,229 +208,93 @@ func ScanPorts() []PortInfo {
 	<-done
 
 	// Sort by port number for consistent ordering
-	sort.Slice(activePorts, func(i, j int) bool {
-		return activePorts[i].Port < activePorts[j].Port
-	})
+	sortPorts(activePorts)
 
-	return activePorts
+	return activePorts, ctx.Err()
 }
 
-func isPortOpen(port int) bool {
-	timeout := AppConfig.PortTimeout
-
-	// Check IPv4 first (most common)
-	conn4, err4 := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", port), timeout)
-	if err4 == nil {
-		conn4.Close()
-		return true
-	}
-
-	// Check IPv6 (::1) for ports that only listen on IPv6
-	conn6, err6 := net.DialTimeout("tcp", fmt.Sprintf("[::1]:%d", port), timeout)
-	if err6 == nil {
-		conn6.Close()
-		return true
-	}
-
-	return false
+// probeTarget is a local address the dial prober connects to
+type probeTarget struct {
+	Address  string
+	Family   string
+	Loopback bool
 }
 
-func getCachedNetstatOutput(ctx context.Context) (string, error) {
-	// Check cache first
-	netstatCacheMu.RLock()
-	if time.Since(netstatCacheTime) < netstatCacheTTL && netstatCache != "" {
-		cached := netstatCache
-		netstatCacheMu.RUnlock()
-		return cached, nil
+// localProbeTargets returns the loopback addresses followed by every unicast
+// address on an interface that is up, so services bound only to a LAN or
+// docker bridge address are found too
+func localProbeTargets() []probeTarget {
+	targets := []probeTarget{
+		{Address: "127.0.0.1", Family: FamilyIPv4, Loopback: true},
+		{Address: "::1", Family: FamilyIPv6, Loopback: true},
 	}
-	netstatCacheMu.RUnlock()
 
-	// Cache miss or expired - run netstat
-	cmd := exec.CommandContext(ctx, "netstat", "-ano")
-	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
-	output, err := cmd.Output()
+	ifaces, err := net.Interfaces()
 	if err != nil {
-		return "", err
+		return targets
 	}
 
-	// Update cache
-	netstatCacheMu.Lock()
-	netstatCache = string(output)
-	netstatCacheTime = time.Now()
-	netstatCacheMu.Unlock()
-
-	return string(output), nil
-}
-
-func clearNetstatCache() {
-	netstatCacheMu.Lock()
-	netstatCache = ""
-	netstatCacheTime = time.Time{}
-	netstatCacheMu.Unlock()
-}
-
-func getProcessInfo(port int) (string, string) {
-	ctx, cancel := context.WithTimeout(context.Background(), AppConfig.CommandTimeout)
-	defer cancel()
-
-	var output string
-	var err error
-
-	if runtime.GOOS == "windows" {
-		output, err = getCachedNetstatOutput(ctx)
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
 		if err != nil {
-			if ctx.Err() == context.DeadlineExceeded {
-				return "Timeout", "Timeout"
-			}
-			return "Unknown", "Unknown"
+			continue
 		}
-	} else {
-		cmd := exec.CommandContext(ctx, "lsof", "-i", fmt.Sprintf(":%d", port))
-		outputBytes, cmdErr := cmd.Output()
-		if cmdErr != nil {
-			if ctx.Err() == context.DeadlineExceeded {
-				return "Timeout", "Timeout"
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			// Link-local addresses need a zone to dial and rarely host dev services
+			if !ok || ipNet.IP.IsLoopback() || ipNet.IP.IsLinkLocalUnicast() {
+				continue
 			}
-			return "Unknown", "Unknown"
-		}
-		output = string(outputBytes)
-	}
-
-	// Use regex for exact port match - handles LISTENING, ESTABLISHED, end of line, and CRLF
-	// Match patterns like ":80 ", ":80\t", ":80\r", or ":80" at end of line
-	portPattern := regexp.MustCompile(fmt.Sprintf(`[:\s]%d(?:[\s\t\r]|$)`, port))
-
-	lines := strings.Split(output, "\n")
-	for _, line := range lines {
-		if portPattern.MatchString(line) {
-			if runtime.GOOS == "windows" {
-				fields := strings.Fields(line)
-				if len(fields) >= 5 {
-					pid := fields[len(fields)-1]
-					process := getProcessName(pid)
-					return pid, process
-				}
-			} else {
-				fields := strings.Fields(line)
-				if len(fields) >= 2 {
-					process := fields[0]
-					pid := fields[1]
-					return pid, process
-				}
+			family := FamilyIPv6
+			if ipNet.IP.To4() != nil {
+				family = FamilyIPv4
 			}
+			targets = append(targets, probeTarget{Address: ipNet.IP.String(), Family: family})
 		}
 	}
 
-	return "Unknown", "Unknown"
+	return targets
 }
 
-func getProcessName(pid string) string {
-	ctx, cancel := context.WithTimeout(context.Background(), AppConfig.CommandTimeout)
-	defer cancel()
-
-	if runtime.GOOS == "windows" {
-		cmd := exec.CommandContext(ctx, "tasklist", "/fi", fmt.Sprintf("PID eq %s", pid), "/fo", "csv")
-		// Hide console window on Windows
-		cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
-		output, err := cmd.Output()
-		if err != nil {
-			if ctx.Err() == context.DeadlineExceeded {
-				return "Timeout"
-			}
-			return "Unknown"
+// probePort dials the port on every target and returns the bind addresses
+// that accepted. A dial cannot see the real bind address, so when every
+// address of a family accepts, the service is assumed to be bound to the
+// wildcard address (0.0.0.0 or ::).
+func probePort(port int, targets []probeTarget) []probeTarget {
+	accepted := make(map[string][]probeTarget)
+	counts := make(map[string]int)
+	for _, t := range targets {
+		counts[t.Family]++
+		if isPortOpen(t.Address, port) {
+			accepted[t.Family] = append(accepted[t.Family], t)
 		}
+	}
 
-		lines := strings.Split(string(output), "\n")
-		if len(lines) >= 2 {
-			fields := strings.Split(lines[1], ",")
-			if len(fields) >= 1 {
-				return strings.Trim(fields[0], "\"")
+	var binds []probeTarget
+	for _, family := range []string{FamilyIPv4, FamilyIPv6} {
+		hits := accepted[family]
+		if len(hits) > 1 && len(hits) == counts[family] {
+			wildcard := "0.0.0.0"
+			if family == FamilyIPv6 {
+				wildcard = "::"
 			}
+			binds = append(binds, probeTarget{Address: wildcard, Family: family})
+			continue
 		}
-	} else {
-		cmd := exec.CommandContext(ctx, "ps", "-p", pid, "-o", "comm=")
-		output, err := cmd.Output()
-		if err != nil {
-			if ctx.Err() == context.DeadlineExceeded {
-				return "Timeout"
-			}
-			return "Unknown"
-		}
-		return strings.TrimSpace(string(output))
+		binds = append(binds, hits...)
 	}
-
-	return "Unknown"
+	return binds
 }
 
-func KillProcess(pid string) error {
-	// Validate PID is a valid positive integer
-	pidNum, err := strconv.Atoi(pid)
-	if err != nil || pidNum <= 0 {
-		return fmt.Errorf("invalid PID: %q", pid)
-	}
-
-	// Protect against killing critical system processes
-	if runtime.GOOS == "windows" && pidNum <= 4 {
-		return fmt.Errorf("cannot kill system process (PID %d)", pidNum)
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), AppConfig.CommandTimeout)
-	defer cancel()
-
-	var cmd *exec.Cmd
-
-	if runtime.GOOS == "windows" {
-		cmd = exec.CommandContext(ctx, "taskkill", "/PID", pid, "/F")
-		// Hide console window on Windows
-		cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
-	} else {
-		cmd = exec.CommandContext(ctx, "kill", "-9", pid)
-	}
-
-	err = cmd.Run()
+// isPortOpen reports whether a TCP dial to address:port succeeds
+func isPortOpen(address string, port int) bool {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(address, strconv.Itoa(port)), AppConfig().PortTimeout)
 	if err != nil {
-		return fmt.Errorf("failed to kill process %s: %w", pid, err)
-	}
-
-	// Clear netstat cache since process state changed
-	clearNetstatCache()
-
-	// Verify process is actually killed by checking if PID still exists
-	return verifyProcessKilled(pid)
-}
-
-func verifyProcessKilled(pid string) error {
-	// Try multiple times with increasing delays
-	maxAttempts := AppConfig.KillVerifyAttempts
-	for attempt := 0; attempt < maxAttempts; attempt++ {
-		time.Sleep(time.Duration(int(AppConfig.KillVerifyBaseDelay.Milliseconds())*(attempt+1)) * time.Millisecond)
-
-		ctx, cancel := context.WithTimeout(context.Background(), AppConfig.CommandTimeout)
-
-		var cmd *exec.Cmd
-		if runtime.GOOS == "windows" {
-			cmd = exec.CommandContext(ctx, "tasklist", "/fi", fmt.Sprintf("PID eq %s", pid), "/fo", "csv", "/nh")
-			cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
-		} else {
-			cmd = exec.CommandContext(ctx, "ps", "-p", pid, "-o", "pid=")
-		}
-
-		output, err := cmd.Output()
-		cancel() // Release context resources
-
-		if runtime.GOOS == "windows" {
-			// On Windows, check if tasklist returns actual process info
-			outputStr := strings.TrimSpace(string(output))
-			if err != nil || outputStr == "" || !strings.Contains(outputStr, pid) {
-				return nil // Process killed successfully
-			}
-		} else {
-			// On Unix, if command fails or output empty, process doesn't exist
-			if err != nil || strings.TrimSpace(string(output)) == "" {
-				return nil // Process killed successfully
-			}
-		}
+		return false
 	}
-
-	return fmt.Errorf("process %s still running after %d kill attempts", pid, maxAttempts)
+	conn.Close()
+	return true
 }
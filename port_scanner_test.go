@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// fakeScanner returns fixed results, standing in for a real backend
+type fakeScanner struct {
+	ports []PortInfo
+	err   error
+}
+
+func (fakeScanner) Name() string { return "fake" }
+
+func (s fakeScanner) Scan(ctx context.Context) ([]PortInfo, error) {
+	return s.ports, s.err
+}
+
+func TestScanPortsUsesInjectedScanner(t *testing.T) {
+	ports := []PortInfo{
+		{Port: 3000, Protocol: ProtoTCP, LocalAddress: "127.0.0.1", Family: FamilyIPv4, PID: "1234", Process: "node", Status: "Active"},
+		{Port: 5353, Protocol: ProtoUDP, LocalAddress: "0.0.0.0", Family: FamilyIPv4, PID: "678", Process: "mdns", Status: "Active"},
+	}
+	errScan := errors.New("scan failed")
+	total := AppConfig().PortSet().Count()
+
+	tests := []struct {
+		name         string
+		scanner      fakeScanner
+		wantProgress []ScanProgress
+	}{
+		{
+			name:         "ports",
+			scanner:      fakeScanner{ports: ports},
+			wantProgress: []ScanProgress{{Probed: total, Total: total, Found: ports}},
+		},
+		{
+			name:         "no ports",
+			scanner:      fakeScanner{},
+			wantProgress: []ScanProgress{{Probed: total, Total: total}},
+		},
+		{
+			name:    "error",
+			scanner: fakeScanner{err: errScan},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress := make(chan ScanProgress, 10)
+			got, err := ScanPorts(context.Background(), ScanOptions{Scanner: tt.scanner, Progress: progress})
+			if !errors.Is(err, tt.scanner.err) {
+				t.Fatalf("ScanPorts() error = %v, want %v", err, tt.scanner.err)
+			}
+			if !reflect.DeepEqual(got, tt.scanner.ports) {
+				t.Errorf("ScanPorts() = %+v, want %+v", got, tt.scanner.ports)
+			}
+
+			// The channel is closed when the scan finishes
+			var events []ScanProgress
+			for p := range progress {
+				events = append(events, p)
+			}
+			if !reflect.DeepEqual(events, tt.wantProgress) {
+				t.Errorf("progress = %+v, want %+v", events, tt.wantProgress)
+			}
+		})
+	}
+}
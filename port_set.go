@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PortRange is an inclusive range of ports; a single port has Start == End
+type PortRange struct {
+	Start int
+	End   int
+}
+
+// PortSet is a sorted list of non-overlapping, non-adjacent port ranges
+type PortSet []PortRange
+
+// PortPreset is a named port list that can be used wherever ports are given
+type PortPreset struct {
+	Name  string
+	Ports string
+}
+
+// DefaultPortPreset is scanned unless the configuration says otherwise
+const DefaultPortPreset = "dev"
+
+// PortPresets are offered in the toolbar, in this order. Presets are plain
+// port lists, so "web, 27017" is as valid as "1-1024, 5432".
+var PortPresets = []PortPreset{
+	// Well-known services below 1025, then everything dev servers, databases,
+	// brokers and container tooling bind up to 9999, and a few ports above
+	{Name: "dev", Ports: "21, 22, 25, 53, 80, 110, 143, 389, 443, 445, 465, 587, 631, 636, 993, 995, 1025-9999, 11211, 15672, 19000-19006, 27017-27019, 50051"},
+	// HTTP servers, frontend tooling and framework defaults
+	{Name: "web", Ports: "80, 443, 3000-3010, 4200, 5000-5010, 5173, 8000-8100, 8443, 8888, 9000"},
+	// Relational, document, key-value and search stores
+	{Name: "databases", Ports: "1433, 1521, 3306, 5432, 5984, 6379, 7474, 7687, 8086, 9042, 9200, 11211, 26257, 27017-27019, 28015"},
+	{Name: "all", Ports: "1-65535"},
+}
+
+// lookupPortPreset finds a preset by name, ignoring case
+func lookupPortPreset(name string) (PortPreset, bool) {
+	for _, preset := range PortPresets {
+		if strings.EqualFold(preset.Name, name) {
+			return preset, true
+		}
+	}
+	return PortPreset{}, false
+}
+
+// ParsePortSet parses a comma-separated list of ports, START-END ranges and
+// preset names, e.g. "1-1024, 3000-3999, 5432, databases"
+func ParsePortSet(spec string) (PortSet, error) {
+	return parsePortSet(spec, true)
+}
+
+func parsePortSet(spec string, allowPresets bool) (PortSet, error) {
+	var ranges []PortRange
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if preset, ok := lookupPortPreset(item); ok && allowPresets {
+			// Presets may not refer to other presets
+			set, err := parsePortSet(preset.Ports, false)
+			if err != nil {
+				return nil, fmt.Errorf("preset %s: %w", preset.Name, err)
+			}
+			ranges = append(ranges, set...)
+			continue
+		}
+		r, err := parsePortRange(item)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("no ports given")
+	}
+	return normalizePortRanges(ranges), nil
+}
+
+// parsePortRange parses "START-END", or a single port
+func parsePortRange(s string) (PortRange, error) {
+	startStr, endStr, isRange := strings.Cut(s, "-")
+	start, err := strconv.Atoi(strings.TrimSpace(startStr))
+	if err != nil {
+		return PortRange{}, fmt.Errorf("invalid port or range %q (want PORT or START-END)", s)
+	}
+	end := start
+	if isRange {
+		if end, err = strconv.Atoi(strings.TrimSpace(endStr)); err != nil {
+			return PortRange{}, fmt.Errorf("invalid port or range %q (want PORT or START-END)", s)
+		}
+	}
+	if start < 1 || start > 65535 || end < 1 || end > 65535 {
+		return PortRange{}, fmt.Errorf("port range %q is outside 1-65535", s)
+	}
+	if end < start {
+		return PortRange{}, fmt.Errorf("port range %q ends before it starts", s)
+	}
+	return PortRange{Start: start, End: end}, nil
+}
+
+// normalizePortRanges sorts ranges and merges overlapping or adjacent ones
+func normalizePortRanges(ranges []PortRange) PortSet {
+	slices.SortFunc(ranges, func(a, b PortRange) int { return a.Start - b.Start })
+	set := PortSet{ranges[0]}
+	for _, r := range ranges[1:] {
+		last := &set[len(set)-1]
+		if r.Start <= last.End+1 {
+			last.End = max(last.End, r.End)
+			continue
+		}
+		set = append(set, r)
+	}
+	return set
+}
+
+// Contains reports whether port is in the set
+func (ps PortSet) Contains(port int) bool {
+	_, found := slices.BinarySearchFunc(ps, port, func(r PortRange, port int) int {
+		switch {
+		case r.End < port:
+			return -1
+		case r.Start > port:
+			return 1
+		}
+		return 0
+	})
+	return found
+}
+
+// Count returns the number of ports in the set
+func (ps PortSet) Count() int {
+	n := 0
+	for _, r := range ps {
+		n += r.End - r.Start + 1
+	}
+	return n
+}
+
+// String formats the set the way ParsePortSet reads it
+func (ps PortSet) String() string {
+	items := make([]string, len(ps))
+	for i, r := range ps {
+		if r.Start == r.End {
+			items[i] = strconv.Itoa(r.Start)
+		} else {
+			items[i] = fmt.Sprintf("%d-%d", r.Start, r.End)
+		}
+	}
+	return strings.Join(items, ", ")
+}
@@ -0,0 +1,74 @@
+package main
+
+import "testing"
+
+func TestParsePortSet(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    string // the set as formatted by String
+		count   int
+		wantErr bool
+	}{
+		{spec: "3000", want: "3000", count: 1},
+		{spec: "3000-3999, 5432", want: "3000-3999, 5432", count: 1001},
+		{spec: "5432,80, 81-90 ,85", want: "80-90, 5432", count: 12},
+		{spec: "8000-8100, 8050-8200, 8201", want: "8000-8201", count: 202},
+		{spec: "databases", want: "1433, 1521, 3306, 5432, 5984, 6379, 7474, 7687, 8086, 9042, 9200, 11211, 26257, 27017-27019, 28015", count: 17},
+		{spec: "WEB, 27017", want: "80, 443, 3000-3010, 4200, 5000-5010, 5173, 8000-8100, 8443, 8888, 9000, 27017", count: 131},
+		{spec: "all", want: "1-65535", count: 65535},
+		{spec: "dev", want: "21-22, 25, 53, 80, 110, 143, 389, 443, 445, 465, 587, 631, 636, 993, 995, 1025-9999, 11211, 15672, 19000-19006, 27017-27019, 50051", count: 9004},
+		{spec: "", wantErr: true},
+		{spec: " , ", wantErr: true},
+		{spec: "0", wantErr: true},
+		{spec: "65536", wantErr: true},
+		{spec: "10-5", wantErr: true},
+		{spec: "3000-", wantErr: true},
+		{spec: "http", wantErr: true},
+		{spec: "web, nope", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			set, err := ParsePortSet(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePortSet(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := set.String(); got != tt.want {
+				t.Errorf("ParsePortSet(%q) = %q, want %q", tt.spec, got, tt.want)
+			}
+			if got := set.Count(); got != tt.count {
+				t.Errorf("ParsePortSet(%q).Count() = %d, want %d", tt.spec, got, tt.count)
+			}
+		})
+	}
+}
+
+func TestPortSetContains(t *testing.T) {
+	set, err := ParsePortSet("22, 3000-3999, 5432")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		port int
+		want bool
+	}{
+		{21, false},
+		{22, true},
+		{23, false},
+		{2999, false},
+		{3000, true},
+		{3500, true},
+		{3999, true},
+		{4000, false},
+		{5432, true},
+		{65535, false},
+	}
+	for _, tt := range tests {
+		if got := set.Contains(tt.port); got != tt.want {
+			t.Errorf("Contains(%d) = %v, want %v", tt.port, got, tt.want)
+		}
+	}
+}
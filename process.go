@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// The platform-specific halves of the process-inspection API live in
+// process_windows.go (netstat/tasklist, OpenProcess/TerminateProcess) and
+// process_unix.go (procfs or lsof, kill(2)). Each provides:
+//
+//	newCommand(ctx, name, args...)    external command with platform defaults
+//	takeProcessSnapshot(ctx)          port -> process map for every listener
+//	signalProcess(pid, force)         asks a PID to exit, or forces it
+//	elevatedTerminate(ctx, pid, grace) signal then force with administrator rights
+//	processRunning(pid)               whether a PID still exists
+//	detachedProcAttr()                attributes for a process that outlives the app
+//	netstatListenOutput(ctx)          raw netstat listing of listeners
+
+// Errors returned by the kill functions, wrapped with details; test them
+// with errors.Is
+var (
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrNoSuchProcess    = errors.New("no such process")
+	ErrProtectedProcess = errors.New("protected process")
+	// ErrElevationCancelled means the user dismissed the password prompt
+	ErrElevationCancelled = errors.New("elevation cancelled")
+)
+
+// elevationTimeout bounds an elevated kill, which waits for the user to
+// answer a password prompt
+const elevationTimeout = 2 * time.Minute
+
+// elevatedForcedExit is the exit code of an elevated termination that had
+// to force the kill after the grace period
+const elevatedForcedExit = 99
+
+// Kill policies accepted by Config.KillPolicy
+const (
+	// KillPolicyGraceful sends SIGTERM (taskkill without /F), waits up to
+	// KillGracePeriod and only then escalates to SIGKILL
+	KillPolicyGraceful = "graceful"
+	// KillPolicyForce sends SIGKILL (taskkill /F) straight away
+	KillPolicyForce = "force"
+)
+
+// KillPolicies lists every selectable kill policy
+var KillPolicies = []string{KillPolicyGraceful, KillPolicyForce}
+
+// Signals reported for a termination; on Windows they stand for taskkill
+// without and with /F
+const (
+	SignalTerm = "SIGTERM"
+	SignalKill = "SIGKILL"
+)
+
+// KillProcess terminates a process using the configured kill policy
+func KillProcess(pid string) error {
+	return KillProcessWithPolicy(pid, AppConfig().KillPolicy)
+}
+
+// KillProcessWithPolicy terminates a process using the given kill policy
+func KillProcessWithPolicy(pid, policy string) error {
+	_, err := terminateProcess(pid, policy, false)
+	return err
+}
+
+// KillProcessElevated retries a kill that failed with ErrPermissionDenied
+// with administrator rights: pkexec or sudo -n on Unix, a UAC prompt on
+// Windows. Only this one operation runs elevated.
+func KillProcessElevated(pid, policy string) error {
+	_, err := terminateProcess(pid, policy, true)
+	return err
+}
+
+// terminateProcess applies a kill policy, records the attempt in the audit
+// log and returns the last signal sent. Signals are sent through the
+// elevation helper when elevated is set.
+func terminateProcess(pid, policy string, elevated bool) (signal string, err error) {
+	// Validate PID is a valid positive integer
+	pidNum, err := strconv.Atoi(pid)
+	if err != nil || pidNum <= 0 {
+		return "", fmt.Errorf("invalid PID: %q", pid)
+	}
+
+	audit := beginAudit(pidNum)
+	audit.Elevated = elevated
+	defer func() { audit.finish(signal, err) }()
+
+	// Protect against killing critical system processes
+	if err := checkProtected(pidNum); err != nil {
+		return "", err
+	}
+
+	if elevated {
+		return terminateElevated(pidNum, policy)
+	}
+
+	if policy == KillPolicyGraceful {
+		err := sendTermination(pid, false)
+		switch {
+		case errors.Is(err, ErrNoSuchProcess), errors.Is(err, ErrPermissionDenied), errors.Is(err, ErrElevationCancelled):
+			// Forcing would fail the same way
+			return SignalTerm, fmt.Errorf("failed to terminate process %s: %w", pid, err)
+		case err == nil:
+			clearProcessCache()
+			// Give the process a chance to flush and clean up before escalating
+			if len(waitForExit(AppConfig().KillGracePeriod, pid)) == 0 {
+				return SignalTerm, nil
+			}
+		}
+		// Otherwise the graceful request was refused - taskkill without /F
+		// cannot stop console processes - so escalate like KillProcessTree
+	}
+
+	if err := sendTermination(pid, true); err != nil {
+		// The process may have exited on its own just before the SIGKILL
+		if !isProcessRunning(pid) {
+			return SignalKill, nil
+		}
+		return SignalKill, fmt.Errorf("failed to kill process %s: %w", pid, err)
+	}
+
+	// Clear cached command output since process state changed
+	clearProcessCache()
+
+	// Verify process is actually killed by checking if PID still exists
+	return SignalKill, verifyProcessKilled(pid)
+}
+
+// sendTermination asks a process to exit, or forces it to when force is set
+func sendTermination(pid string, force bool) error {
+	pidNum, err := strconv.Atoi(pid)
+	if err != nil || pidNum <= 0 {
+		return fmt.Errorf("invalid PID: %q", pid)
+	}
+	return signalProcess(pidNum, force)
+}
+
+// terminateElevated applies a kill policy as one elevated operation, so the
+// user answers a single password or UAC prompt: the helper sends the
+// graceful request, waits out KillGracePeriod and forces the kill itself
+func terminateElevated(pid int, policy string) (string, error) {
+	grace := time.Duration(0)
+	if policy == KillPolicyGraceful {
+		grace = AppConfig().KillGracePeriod
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), elevationTimeout+grace)
+	defer cancel()
+
+	forced, err := elevatedTerminate(ctx, pid, grace)
+	signal := SignalTerm
+	if forced {
+		signal = SignalKill
+	}
+	if err != nil {
+		return signal, fmt.Errorf("failed to terminate process %d: %w", pid, err)
+	}
+	clearProcessCache()
+	return signal, verifyProcessKilled(strconv.Itoa(pid))
+}
+
+// isProcessRunning reports whether a PID still exists
+func isProcessRunning(pid string) bool {
+	pidNum, err := strconv.Atoi(pid)
+	return err == nil && pidNum > 0 && processRunning(pidNum)
+}
+
+// waitForExit polls every KillVerifyBaseDelay until all PIDs have exited or
+// the timeout elapses, and returns the PIDs still running
+func waitForExit(timeout time.Duration, pids ...string) []string {
+	deadline := time.Now().Add(timeout)
+	remaining := pids
+	for {
+		var running []string
+		for _, pid := range remaining {
+			if isProcessRunning(pid) {
+				running = append(running, pid)
+			}
+		}
+		remaining = running
+
+		if len(remaining) == 0 || !time.Now().Before(deadline) {
+			return remaining
+		}
+		time.Sleep(AppConfig().KillVerifyBaseDelay)
+	}
+}
+
+func verifyProcessKilled(pid string) error {
+	cfg := AppConfig()
+
+	// Try multiple times with increasing delays
+	maxAttempts := cfg.KillVerifyAttempts
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		time.Sleep(time.Duration(int(cfg.KillVerifyBaseDelay.Milliseconds())*(attempt+1)) * time.Millisecond)
+
+		if !isProcessRunning(pid) {
+			return nil // Process killed successfully
+		}
+	}
+
+	return fmt.Errorf("process %s still running after %d kill attempts", pid, maxAttempts)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProcessDetails describes a process beyond its PID and short name, so ports
+// held by several "node" or "python" processes can be told apart.
+// Fields the platform cannot report are left at their zero value.
+type ProcessDetails struct {
+	PID        int       `json:"pid"`
+	PPID       int       `json:"ppid"`
+	Name       string    `json:"name"`
+	Exe        string    `json:"exe"`  // executable path
+	Args       []string  `json:"args"` // full argv
+	Cwd        string    `json:"cwd"`
+	User       string    `json:"user"`
+	StartTime  time.Time `json:"start_time"`
+	RSS        uint64    `json:"rss"`         // resident set size in bytes
+	CPUPercent float64   `json:"cpu_percent"` // average CPU usage since the process started
+}
+
+// CommandLine joins the argv for display
+func (d *ProcessDetails) CommandLine() string {
+	return strings.Join(d.Args, " ")
+}
+
+// GetProcessDetails gathers details for a PID from /proc on Linux, ps on
+// other Unix systems and CIM on Windows
+func GetProcessDetails(pid string) (*ProcessDetails, error) {
+	pidNum, err := strconv.Atoi(pid)
+	if err != nil || pidNum <= 0 {
+		return nil, fmt.Errorf("invalid PID: %q", pid)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), AppConfig().CommandTimeout)
+	defer cancel()
+
+	details, err := readProcessDetails(ctx, pidNum)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timed out reading details for PID %d", pidNum)
+		}
+		return nil, fmt.Errorf("failed to read details for PID %d: %w", pidNum, err)
+	}
+	return details, nil
+}
+
+// String renders the details as aligned "Field: value" lines
+func (d *ProcessDetails) String() string {
+	orUnknown := func(s string) string {
+		if s == "" {
+			return "—"
+		}
+		return s
+	}
+
+	start := "—"
+	if !d.StartTime.IsZero() {
+		start = fmt.Sprintf("%s (up %s)", d.StartTime.Format("2006-01-02 15:04:05"),
+			time.Since(d.StartTime).Round(time.Second))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "PID:      %d\n", d.PID)
+	fmt.Fprintf(&b, "Parent:   %d\n", d.PPID)
+	fmt.Fprintf(&b, "Name:     %s\n", orUnknown(d.Name))
+	fmt.Fprintf(&b, "Command:  %s\n", orUnknown(d.CommandLine()))
+	fmt.Fprintf(&b, "Cwd:      %s\n", orUnknown(d.Cwd))
+	fmt.Fprintf(&b, "User:     %s\n", orUnknown(d.User))
+	fmt.Fprintf(&b, "Started:  %s\n", start)
+	fmt.Fprintf(&b, "Memory:   %s\n", formatBytes(d.RSS))
+	fmt.Fprintf(&b, "CPU:      %.1f%%", d.CPUPercent)
+	return b.String()
+}
+
+// formatBytes renders a byte count with a binary unit suffix
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,160 @@
+//go:build linux
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockTicks is USER_HZ, the unit of the times in /proc/<pid>/stat. It is
+// 100 on every architecture Go supports.
+const clockTicks = 100
+
+// readProcessDetails reads /proc/<pid>/{cmdline,cwd,exe,status,stat}
+func readProcessDetails(_ context.Context, pid int) (*ProcessDetails, error) {
+	dir := filepath.Join("/proc", strconv.Itoa(pid))
+
+	status, err := readProcStatus(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &ProcessDetails{
+		PID:  pid,
+		Name: status["Name"],
+	}
+	d.PPID, _ = strconv.Atoi(status["PPid"])
+
+	// Uid: real effective saved fs
+	if uids := strings.Fields(status["Uid"]); len(uids) > 0 {
+		d.User = uids[0]
+		if u, err := user.LookupId(uids[0]); err == nil {
+			d.User = u.Username
+		}
+	}
+
+	// VmRSS: 1234 kB (absent for kernel threads)
+	if rss := strings.Fields(status["VmRSS"]); len(rss) > 0 {
+		if kb, err := strconv.ParseUint(rss[0], 10, 64); err == nil {
+			d.RSS = kb * 1024
+		}
+	}
+
+	if cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline")); err == nil {
+		d.Args = strings.Split(strings.TrimRight(string(cmdline), "\x00"), "\x00")
+		if len(d.Args) == 1 && d.Args[0] == "" {
+			d.Args = nil
+		}
+	}
+
+	// Need the same privileges as reading the fd table - left empty otherwise
+	d.Cwd, _ = os.Readlink(filepath.Join(dir, "cwd"))
+	d.Exe, _ = os.Readlink(filepath.Join(dir, "exe"))
+
+	if start, cpu, err := readProcStat(dir); err == nil {
+		d.StartTime = start
+		d.CPUPercent = cpu
+	}
+
+	return d, nil
+}
+
+// readProcessEnviron reads the environment a process was started with from
+// /proc/<pid>/environ, which is only readable for the current user's processes
+func readProcessEnviron(pid int) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "environ"))
+	if err != nil {
+		return nil, err
+	}
+	var env []string
+	for _, kv := range strings.Split(string(data), "\x00") {
+		if kv != "" {
+			env = append(env, kv)
+		}
+	}
+	return env, nil
+}
+
+// readProcStatus parses /proc/<pid>/status "Key:\tvalue" lines
+func readProcStatus(dir string) (map[string]string, error) {
+	f, err := os.Open(filepath.Join(dir, "status"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	status := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if ok {
+			status[key] = strings.TrimSpace(value)
+		}
+	}
+	return status, scanner.Err()
+}
+
+// readProcStat derives the start time and lifetime CPU usage from
+// /proc/<pid>/stat, the same way ps computes %CPU
+func readProcStat(dir string) (time.Time, float64, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	// comm may contain spaces and parentheses - fields start after the last ')'
+	stat := string(data)
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return time.Time{}, 0, fmt.Errorf("malformed stat for %s", dir)
+	}
+	fields := strings.Fields(stat[end+1:])
+	// fields[0] is field 3 (state): utime=14, stime=15, starttime=22
+	if len(fields) < 20 {
+		return time.Time{}, 0, fmt.Errorf("malformed stat for %s", dir)
+	}
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+	startTicks, _ := strconv.ParseUint(fields[19], 10, 64)
+
+	bootTime, err := readBootTime()
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	start := bootTime.Add(time.Duration(startTicks) * time.Second / clockTicks)
+	cpu := 0.0
+	if elapsed := time.Since(start).Seconds(); elapsed > 0 {
+		cpu = float64(utime+stime) / clockTicks / elapsed * 100
+	}
+	return start, cpu, nil
+}
+
+// readBootTime returns the boot time recorded as "btime" in /proc/stat
+func readBootTime() (time.Time, error) {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "btime "); ok {
+			secs, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return time.Unix(secs, 0), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("btime not found in /proc/stat")
+}
@@ -0,0 +1,69 @@
+//go:build !linux && !windows
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// readProcessDetails queries ps for the process and lsof for its cwd
+func readProcessDetails(ctx context.Context, pid int) (*ProcessDetails, error) {
+	pidStr := strconv.Itoa(pid)
+
+	// lstart spans five columns: "Mon Oct 13 10:00:00 2025"
+	output, err := newCommand(ctx, "ps", "-p", pidStr, "-o", "ppid=,user=,rss=,%cpu=,lstart=,comm=").Output()
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Fields(string(output))
+	if len(fields) < 10 {
+		return nil, fmt.Errorf("unexpected ps output: %q", strings.TrimSpace(string(output)))
+	}
+
+	d := &ProcessDetails{
+		PID:  pid,
+		User: fields[1],
+		Name: strings.Join(fields[9:], " "),
+	}
+	// comm is the executable path on macOS
+	if i := strings.LastIndexByte(d.Name, '/'); i >= 0 {
+		d.Exe = d.Name
+		d.Name = d.Name[i+1:]
+	}
+	d.PPID, _ = strconv.Atoi(fields[0])
+	if kb, err := strconv.ParseUint(fields[2], 10, 64); err == nil {
+		d.RSS = kb * 1024
+	}
+	d.CPUPercent, _ = strconv.ParseFloat(fields[3], 64)
+	if start, err := time.ParseInLocation("Mon Jan _2 15:04:05 2006", strings.Join(fields[4:9], " "), time.Local); err == nil {
+		d.StartTime = start
+	}
+
+	// ps joins argv with spaces, so arguments containing spaces are split
+	if args, err := newCommand(ctx, "ps", "-p", pidStr, "-o", "command=").Output(); err == nil {
+		d.Args = strings.Fields(string(args))
+	}
+
+	// lsof -Fn prints the cwd path on a line prefixed with "n"
+	if cwd, err := newCommand(ctx, "lsof", "-a", "-p", pidStr, "-d", "cwd", "-Fn").Output(); err == nil {
+		for _, line := range strings.Split(string(cwd), "\n") {
+			if strings.HasPrefix(line, "n") {
+				d.Cwd = line[1:]
+				break
+			}
+		}
+	}
+
+	return d, nil
+}
+
+// readProcessEnviron is not available without /proc; a restarted process
+// inherits DevPorts Pro's environment instead
+func readProcessEnviron(pid int) ([]string, error) {
+	return nil, errors.New("process environment is only available on Linux")
+}
@@ -0,0 +1,109 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// processDetailsScript prints one Win32_Process and its owner as JSON
+const processDetailsScript = `$p = Get-CimInstance Win32_Process -Filter "ProcessId=%d"
+if (-not $p) { exit 1 }
+$o = Invoke-CimMethod -InputObject $p -MethodName GetOwner
+[pscustomobject]@{
+  PPID = $p.ParentProcessId
+  Name = $p.Name
+  Exe = $p.ExecutablePath
+  CommandLine = $p.CommandLine
+  Start = $p.CreationDate.ToUniversalTime().ToString('o')
+  RSS = $p.WorkingSetSize
+  CPUSeconds = ($p.KernelModeTime + $p.UserModeTime) / 1e7
+  User = $o.User
+  Domain = $o.Domain
+} | ConvertTo-Json -Compress`
+
+// cimProcess is the JSON printed by processDetailsScript
+type cimProcess struct {
+	PPID        int
+	Name        string
+	Exe         string
+	CommandLine string
+	Start       string
+	RSS         uint64
+	CPUSeconds  float64
+	User        string
+	Domain      string
+}
+
+// readProcessDetails queries CIM through PowerShell. Windows does not expose
+// another process's working directory, so Cwd stays empty.
+func readProcessDetails(ctx context.Context, pid int) (*ProcessDetails, error) {
+	output, err := newCommand(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command",
+		fmt.Sprintf(processDetailsScript, pid)).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var p cimProcess
+	if err := json.Unmarshal(output, &p); err != nil {
+		return nil, errors.New("unexpected CIM output")
+	}
+
+	d := &ProcessDetails{
+		PID:  pid,
+		PPID: p.PPID,
+		Name: p.Name,
+		Exe:  p.Exe,
+		User: p.User,
+		RSS:  p.RSS,
+	}
+	if p.Domain != "" && p.User != "" {
+		d.User = p.Domain + `\` + p.User
+	}
+	if p.CommandLine != "" {
+		d.Args = splitWindowsCommandLine(p.CommandLine)
+	}
+	if start, err := time.Parse(time.RFC3339Nano, p.Start); err == nil {
+		d.StartTime = start.Local()
+		if elapsed := time.Since(start).Seconds(); elapsed > 0 {
+			d.CPUPercent = p.CPUSeconds / elapsed * 100
+		}
+	}
+
+	return d, nil
+}
+
+// readProcessEnviron is not available on Windows without reading another
+// process's PEB; a restarted process inherits DevPorts Pro's environment
+func readProcessEnviron(pid int) ([]string, error) {
+	return nil, errors.New("process environment is only available on Linux")
+}
+
+// splitWindowsCommandLine splits a command line on unquoted spaces, keeping
+// quoted arguments such as "C:\Program Files\node.exe" together
+func splitWindowsCommandLine(cmdline string) []string {
+	var args []string
+	var current []rune
+	inQuotes := false
+	for _, r := range cmdline {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == ' ' && !inQuotes:
+			if len(current) > 0 {
+				args = append(args, string(current))
+				current = current[:0]
+			}
+		default:
+			current = append(current, r)
+		}
+	}
+	if len(current) > 0 {
+		args = append(args, string(current))
+	}
+	return args
+}
@@ -0,0 +1,33 @@
+//go:build windows
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitWindowsCommandLine(t *testing.T) {
+	tests := []struct {
+		cmdline string
+		want    []string
+	}{
+		{cmdline: `node.exe server.js`, want: []string{"node.exe", "server.js"}},
+		{
+			cmdline: `"C:\Program Files\nodejs\node.exe" "C:\Users\dev\my app\server.js" --port 3000`,
+			want:    []string{`C:\Program Files\nodejs\node.exe`, `C:\Users\dev\my app\server.js`, "--port", "3000"},
+		},
+		{cmdline: `python.exe  -m   http.server `, want: []string{"python.exe", "-m", "http.server"}},
+		{cmdline: `cmd.exe /c "echo hi"`, want: []string{"cmd.exe", "/c", "echo hi"}},
+		{cmdline: `app.exe --name="a b"`, want: []string{"app.exe", "--name=a b"}},
+		{cmdline: ``, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cmdline, func(t *testing.T) {
+			if got := splitWindowsCommandLine(tt.cmdline); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitWindowsCommandLine(%q) = %q, want %q", tt.cmdline, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrPortNotInUse is returned when nothing is listening on a port
+var ErrPortNotInUse = errors.New("no process is listening on port")
+
+// KillPortError reports a port that is still in use after KillPort
+type KillPortError struct {
+	Port      int
+	Survivors []string // PIDs still holding the port; empty if the holder is hidden
+	Err       error    // failures terminating the original holders, if any
+}
+
+func (e *KillPortError) Error() string {
+	msg := fmt.Sprintf("port %d is still in use", e.Port)
+	if len(e.Survivors) > 0 {
+		msg += fmt.Sprintf(" by PID %s", strings.Join(e.Survivors, ", "))
+	} else {
+		msg += " by a process that cannot be identified"
+	}
+	if e.Err != nil {
+		msg += fmt.Sprintf(": %v", e.Err)
+	}
+	return msg
+}
+
+func (e *KillPortError) Unwrap() error { return e.Err }
+
+// PortHolders returns every PID holding a TCP or UDP socket on port, taken
+// from a fresh process snapshot
+func PortHolders(port int) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), AppConfig().CommandTimeout)
+	defer cancel()
+
+	clearProcessCache()
+	snapshot, err := getProcessSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pids []string
+	for _, protocol := range []string{ProtoTCP, ProtoUDP} {
+		for _, pid := range snapshot.PIDs(protocol, port) {
+			if !slices.Contains(pids, pid) {
+				pids = append(pids, pid)
+			}
+		}
+	}
+	return pids, nil
+}
+
+// KillPort terminates every process holding port using the configured kill
+// policy
+func KillPort(port int) error {
+	return KillPortWithPolicy(port, AppConfig().KillPolicy)
+}
+
+// KillPortWithPolicy terminates every process holding port, e.g. all workers
+// of a pre-fork server, and then checks that the port is actually free. A
+// *KillPortError lists the PIDs still holding it, including replacements
+// spawned by a supervisor.
+func KillPortWithPolicy(port int, policy string) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port: %d (must be 1-65535)", port)
+	}
+
+	pids, err := PortHolders(port)
+	if err != nil {
+		return fmt.Errorf("failed to resolve processes on port %d: %w", port, err)
+	}
+	if len(pids) == 0 {
+		if portListening(port) {
+			return &KillPortError{Port: port}
+		}
+		return fmt.Errorf("%w %d", ErrPortNotInUse, port)
+	}
+
+	// Terminate all holders at once so they share one grace period
+	errs := make([]error, len(pids))
+	var wg sync.WaitGroup
+	for i, pid := range pids {
+		wg.Add(1)
+		go func(i int, pid string) {
+			defer wg.Done()
+			errs[i] = KillProcessWithPolicy(pid, policy)
+		}(i, pid)
+	}
+	wg.Wait()
+
+	survivors, err := PortHolders(port)
+	if err != nil {
+		return fmt.Errorf("failed to verify port %d was released: %w", port, err)
+	}
+	if len(survivors) == 0 && !portListening(port) {
+		return nil
+	}
+	return &KillPortError{Port: port, Survivors: survivors, Err: errors.Join(errs...)}
+}
+
+// portListening reports whether anything still accepts on port. The dial
+// prober also catches sockets whose owner the snapshot cannot see, such as
+// processes of other users.
+func portListening(port int) bool {
+	return len(probePort(port, localProbeTargets())) > 0
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// defaultProtectedNames are init systems, remote access, display servers and
+// container daemons - killing any of them takes down far more than a port
+var defaultProtectedNames = []string{
+	// Init and session management
+	"init", "systemd", "launchd", "kernel_task", "loginwindow", "login",
+	// Remote access
+	"sshd",
+	// Display servers and compositors
+	"Xorg", "Xwayland", "gnome-shell", "kwin_wayland", "kwin_x11",
+	"plasmashell", "gdm", "gdm3", "sddm", "lightdm", "WindowServer",
+	// Container runtimes
+	"dockerd", "containerd", "com.docker.backend", "dockerd.exe",
+	"com.docker.backend.exe",
+	// Windows core processes
+	"System", "smss.exe", "csrss.exe", "wininit.exe", "winlogon.exe",
+	"services.exe", "lsass.exe", "svchost.exe", "dwm.exe",
+}
+
+// defaultProtectedPaths cover executables shipped with the OS itself
+var defaultProtectedPaths = []string{
+	"/sbin/init",
+	"/lib/systemd/**",
+	"/usr/lib/systemd/**",
+	"/System/Library/**",
+	"/usr/libexec/**",
+	`C:\Windows\System32\**`,
+}
+
+// defaultProtectedUsers are the Windows service accounts. root is not listed:
+// dev servers started with sudo and docker-proxy run as root, and the named
+// defaults already cover the daemons that matter.
+var defaultProtectedUsers = []string{"SYSTEM", "LOCAL SERVICE", "NETWORK SERVICE"}
+
+// ProtectionIndex answers whether processes are protected from a single
+// process listing, so the table can check every row without spawning a
+// command per PID
+type ProtectionIndex struct {
+	processes map[int]processEntry
+}
+
+// LoadProtectionIndex lists every process once for protection checks
+func LoadProtectionIndex() (*ProtectionIndex, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), AppConfig().CommandTimeout)
+	defer cancel()
+
+	entries, err := listProcesses(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+	idx := &ProtectionIndex{processes: make(map[int]processEntry, len(entries))}
+	for _, e := range entries {
+		idx.processes[e.PID] = e
+	}
+	return idx, nil
+}
+
+// Reason explains why pid is protected, or returns "" if it may be killed.
+// PIDs missing from the listing are only checked by number.
+func (idx *ProtectionIndex) Reason(pid string) string {
+	pidNum, err := strconv.Atoi(pid)
+	if err != nil || pidNum <= 0 {
+		return ""
+	}
+	e, ok := idx.processes[pidNum]
+	if !ok {
+		e = processEntry{PID: pidNum}
+	}
+	return protectionReason(e)
+}
+
+// checkProtected returns an ErrProtectedProcess error if pid must not be killed
+func checkProtected(pid int) error {
+	// Cheap checks first so the listing is skipped for obvious cases
+	if reason := protectionReason(processEntry{PID: pid}); reason != "" {
+		return fmt.Errorf("%w: %s", ErrProtectedProcess, reason)
+	}
+
+	idx, err := LoadProtectionIndex()
+	if err != nil {
+		// Fail closed: an unverified process could be anything
+		return fmt.Errorf("cannot check whether PID %d is protected: %w", pid, err)
+	}
+	if reason := idx.Reason(strconv.Itoa(pid)); reason != "" {
+		return fmt.Errorf("%w: %s", ErrProtectedProcess, reason)
+	}
+	return nil
+}
+
+// protectionReason matches a process against AppConfig's protection lists
+// and the built-in rules
+func protectionReason(e processEntry) string {
+	cfg := AppConfig()
+
+	if e.PID == os.Getpid() {
+		return fmt.Sprintf("PID %d is DevPorts Pro itself", e.PID)
+	}
+	// Windows reserves PIDs 0-4 for the Idle and System processes
+	if runtime.GOOS == "windows" && e.PID <= 4 {
+		return fmt.Sprintf("PID %d is a Windows system process", e.PID)
+	}
+
+	for _, pid := range cfg.ProtectedPIDs {
+		if e.PID == pid {
+			return fmt.Sprintf("PID %d is protected", e.PID)
+		}
+	}
+	if e.Name != "" {
+		for _, name := range cfg.ProtectedNames {
+			if strings.EqualFold(e.Name, name) {
+				return fmt.Sprintf("%s (PID %d) is a protected process", e.Name, e.PID)
+			}
+		}
+	}
+	if e.Exe != "" {
+		for _, pattern := range cfg.ProtectedPaths {
+			if matchPathGlob(pattern, e.Exe) {
+				return fmt.Sprintf("%s (PID %d) matches protected path %s", e.Exe, e.PID, pattern)
+			}
+		}
+	}
+	if e.User != "" {
+		for _, user := range cfg.ProtectedUsers {
+			if sameUser(e.User, user) {
+				return fmt.Sprintf("PID %d is owned by protected user %s", e.PID, e.User)
+			}
+		}
+	}
+	return ""
+}
+
+// matchPathGlob matches path against a filepath.Match pattern, where a
+// trailing "**" matches anything below the directory. Windows paths are
+// compared case-insensitively.
+func matchPathGlob(pattern, path string) bool {
+	if runtime.GOOS == "windows" {
+		pattern, path = strings.ToLower(pattern), strings.ToLower(path)
+	}
+	if dir, ok := strings.CutSuffix(pattern, "**"); ok {
+		if dir == "" {
+			return true
+		}
+		// Match the pattern's directory against the same number of leading
+		// path elements, then accept whatever follows
+		depth := strings.Count(dir, string(filepath.Separator))
+		parts := strings.SplitAfterN(path, string(filepath.Separator), depth+1)
+		if len(parts) <= depth {
+			return false
+		}
+		matched, _ := filepath.Match(dir, strings.Join(parts[:depth], ""))
+		return matched
+	}
+	matched, _ := filepath.Match(pattern, path)
+	return matched
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestMatchPathGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"/usr/bin/*", "/usr/bin/node", true},
+		{"/usr/bin/*", "/usr/bin/sub/node", false},
+		{"/usr/bin/node", "/usr/bin/node", true},
+		{"/usr/lib/**", "/usr/lib/jvm/bin/java", true},
+		{"/usr/lib/**", "/usr/lib/java", true},
+		{"/usr/lib/**", "/usr/lib", false},
+		{"/usr/lib/**", "/usr/library/java", false},
+		{"/opt/*/bin/**", "/opt/app/bin/server", true},
+		{"/opt/*/bin/**", "/opt/app/lib/server", false},
+		{"**", "/anything/at/all", true},
+		{"/usr/bin/[", "/usr/bin/[", false},
+	}
+
+	for _, tt := range tests {
+		pattern, path := filepath.FromSlash(tt.pattern), filepath.FromSlash(tt.path)
+		t.Run(pattern+" "+path, func(t *testing.T) {
+			if got := matchPathGlob(pattern, path); got != tt.want {
+				t.Errorf("matchPathGlob(%q, %q) = %v, want %v", pattern, path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchPathGlobWindowsCase(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		t.Skip("paths are case-sensitive on this platform")
+	}
+	if !matchPathGlob(`C:\Windows\System32\**`, `c:\windows\system32\svchost.exe`) {
+		t.Error("Windows paths should match case-insensitively")
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LaunchSpec is how a process was started, captured before it is killed so
+// it can be started again
+type LaunchSpec struct {
+	PID      int // the killed process
+	Name     string
+	Exe      string
+	Args     []string
+	Cwd      string
+	Env      []string // nil when unreadable; the restart inherits our environment
+	Ports    []int    // ports it held when it was killed
+	KilledAt time.Time
+}
+
+// CommandLine joins the argv for display
+func (s *LaunchSpec) CommandLine() string {
+	return strings.Join(s.Args, " ")
+}
+
+var (
+	killedLaunches   = make(map[int]*LaunchSpec) // PID -> launch, for this session
+	killedLaunchesMu sync.Mutex
+)
+
+// newLaunchSpec records argv, cwd and environment of a running process from
+// its details. It returns nil when the command line could not be read.
+func newLaunchSpec(details *ProcessDetails, ports []int) *LaunchSpec {
+	if len(details.Args) == 0 {
+		return nil
+	}
+	spec := &LaunchSpec{
+		PID:   details.PID,
+		Name:  details.Name,
+		Exe:   details.Exe,
+		Args:  details.Args,
+		Cwd:   details.Cwd,
+		Ports: ports,
+	}
+	spec.Env, _ = readProcessEnviron(details.PID)
+	return spec
+}
+
+// rememberKilled makes a terminated process available to RestartProcess
+func rememberKilled(spec *LaunchSpec) {
+	if spec == nil {
+		return
+	}
+	killedLaunchesMu.Lock()
+	defer killedLaunchesMu.Unlock()
+	spec.KilledAt = time.Now()
+	killedLaunches[spec.PID] = spec
+}
+
+// KilledProcesses returns the processes killed this session that can be
+// restarted, most recent first
+func KilledProcesses() []*LaunchSpec {
+	killedLaunchesMu.Lock()
+	defer killedLaunchesMu.Unlock()
+
+	specs := make([]*LaunchSpec, 0, len(killedLaunches))
+	for _, spec := range killedLaunches {
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		return specs[i].KilledAt.After(specs[j].KilledAt)
+	})
+	return specs
+}
+
+// RestartProcess starts a killed process again, detached, with its recorded
+// argv, cwd and environment. A port above zero is passed as the PORT
+// environment variable, which most dev servers honour. It returns the new PID.
+func RestartProcess(pid, port int) (int, error) {
+	killedLaunchesMu.Lock()
+	spec, ok := killedLaunches[pid]
+	killedLaunchesMu.Unlock()
+	if !ok {
+		return 0, fmt.Errorf("no recorded command line for PID %d", pid)
+	}
+	if port < 0 || port > 65535 {
+		return 0, fmt.Errorf("invalid port: %d (must be 1-65535)", port)
+	}
+
+	path, err := spec.executable()
+	if err != nil {
+		return 0, err
+	}
+
+	cmd := exec.Command(path)
+	cmd.Args = spec.Args // keeps argv[0] as the process saw it
+	cmd.Dir = spec.Cwd
+	cmd.Env = spec.Env
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	if port > 0 {
+		cmd.Env = setEnv(cmd.Env, "PORT", strconv.Itoa(port))
+	}
+	cmd.SysProcAttr = detachedProcAttr()
+
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("failed to restart %s: %w", spec.Name, err)
+	}
+	newPID := cmd.Process.Pid
+	// Reap the child when it exits. Without a Wait it lingers as a zombie,
+	// which kill(pid, 0) reports as running, so killing it again would
+	// never be verified.
+	go cmd.Wait()
+
+	killedLaunchesMu.Lock()
+	delete(killedLaunches, pid)
+	killedLaunchesMu.Unlock()
+	clearProcessCache()
+	return newPID, nil
+}
+
+// executable resolves the binary to start: the recorded executable if it
+// still exists, else argv[0] relative to the recorded cwd or on PATH
+func (s *LaunchSpec) executable() (string, error) {
+	// A rebuilt binary shows up as "/path/server (deleted)" in /proc
+	if s.Exe != "" && !strings.HasSuffix(s.Exe, " (deleted)") {
+		if _, err := os.Stat(s.Exe); err == nil {
+			return s.Exe, nil
+		}
+	}
+
+	argv0 := s.Args[0]
+	if strings.ContainsAny(argv0, `/\`) {
+		if !filepath.IsAbs(argv0) && s.Cwd != "" {
+			argv0 = filepath.Join(s.Cwd, argv0)
+		}
+		if _, err := os.Stat(argv0); err != nil {
+			return "", fmt.Errorf("executable %s no longer exists", argv0)
+		}
+		return argv0, nil
+	}
+
+	path, err := exec.LookPath(argv0)
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", fmt.Errorf("executable %s not found on PATH", argv0)
+		}
+		return "", err
+	}
+	return path, nil
+}
+
+// setEnv replaces or appends key=value in an environment list
+func setEnv(env []string, key, value string) []string {
+	out := make([]string, 0, len(env)+1)
+	for _, kv := range env {
+		if k, _, ok := strings.Cut(kv, "="); ok && k == key {
+			continue
+		}
+		out = append(out, kv)
+	}
+	return append(out, key+"="+value)
+}
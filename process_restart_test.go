@@ -0,0 +1,142 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSetEnv(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   []string
+		key   string
+		value string
+		want  []string
+	}{
+		{
+			name:  "empty",
+			key:   "PORT",
+			value: "3000",
+			want:  []string{"PORT=3000"},
+		},
+		{
+			name:  "appends a new variable",
+			env:   []string{"HOME=/home/dev", "PATH=/usr/bin"},
+			key:   "PORT",
+			value: "3000",
+			want:  []string{"HOME=/home/dev", "PATH=/usr/bin", "PORT=3000"},
+		},
+		{
+			name:  "overrides an existing variable",
+			env:   []string{"HOME=/home/dev", "PORT=8080", "PATH=/usr/bin"},
+			key:   "PORT",
+			value: "3000",
+			want:  []string{"HOME=/home/dev", "PATH=/usr/bin", "PORT=3000"},
+		},
+		{
+			name:  "drops duplicates",
+			env:   []string{"PORT=8080", "PORT=8081"},
+			key:   "PORT",
+			value: "3000",
+			want:  []string{"PORT=3000"},
+		},
+		{
+			name:  "matches the whole name",
+			env:   []string{"PORTS=1,2", "DB_PORT=5432", "PORT"},
+			key:   "PORT",
+			value: "3000",
+			want:  []string{"PORTS=1,2", "DB_PORT=5432", "PORT", "PORT=3000"},
+		},
+		{
+			name:  "value may contain =",
+			env:   []string{"NODE_OPTIONS=--inspect"},
+			key:   "NODE_OPTIONS",
+			value: "--max-old-space-size=4096",
+			want:  []string{"NODE_OPTIONS=--max-old-space-size=4096"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := slices.Clone(tt.env)
+			if got := setEnv(env, tt.key, tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("setEnv(%q, %q, %q) = %q, want %q", tt.env, tt.key, tt.value, got, tt.want)
+			}
+			if !slices.Equal(env, tt.env) {
+				t.Errorf("setEnv() modified its input: %q", env)
+			}
+		})
+	}
+}
+
+func TestLaunchSpecExecutable(t *testing.T) {
+	dir := t.TempDir()
+	server := filepath.Join(dir, "bin", "server")
+	if err := os.MkdirAll(filepath.Dir(server), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, server, "")
+	t.Setenv("PATH", t.TempDir())
+
+	tests := []struct {
+		name    string
+		spec    LaunchSpec
+		want    string
+		wantErr string // part of the error message
+	}{
+		{
+			name: "recorded executable",
+			spec: LaunchSpec{Exe: server, Args: []string{"node"}, Cwd: "/nonexistent"},
+			want: server,
+		},
+		{
+			name: "relative argv[0] against the working directory",
+			spec: LaunchSpec{Args: []string{"./bin/server", "--port", "3000"}, Cwd: dir},
+			want: server,
+		},
+		{
+			name: "rebuilt binary",
+			spec: LaunchSpec{Exe: server + " (deleted)", Args: []string{"bin/server"}, Cwd: dir},
+			want: server,
+		},
+		{
+			name: "recorded executable gone",
+			spec: LaunchSpec{Exe: filepath.Join(dir, "old"), Args: []string{"bin/server"}, Cwd: dir},
+			want: server,
+		},
+		{
+			name: "absolute argv[0] ignores the working directory",
+			spec: LaunchSpec{Args: []string{server}, Cwd: t.TempDir()},
+			want: server,
+		},
+		{
+			name:    "relative argv[0] gone",
+			spec:    LaunchSpec{Args: []string{"./bin/client"}, Cwd: dir},
+			wantErr: "no longer exists",
+		},
+		{
+			name:    "bare name not on PATH",
+			spec:    LaunchSpec{Args: []string{"devports-test-server"}, Cwd: dir},
+			wantErr: "not found on PATH",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.spec.executable()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("executable() = %q, %v, want an error mentioning %q", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("executable() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	snapshotCache     *ProcessSnapshot
+	snapshotCacheMu   sync.Mutex
+	snapshotCacheTime time.Time
+	snapshotCacheTTL  = 2 * time.Second
+)
+
+// processOwner is the process holding a listening socket
+type processOwner struct {
+	PID     string
+	Process string
+}
+
+// ProcessSnapshot maps listening ports to their owning processes. It is
+// built from a single procfs walk, lsof run or netstat/tasklist pair and
+// shared by every lookup in a scan, instead of spawning a command per port.
+type ProcessSnapshot struct {
+	owners  map[portKey]processOwner // keyed by protocol and port only
+	holders map[portKey][]string     // every PID holding the port, e.g. pre-fork workers
+	names   map[string]string        // PID -> process name
+}
+
+// newProcessSnapshot indexes socket entries by protocol and port, preferring
+// entries with a known PID, and fills missing process names from names
+func newProcessSnapshot(entries []PortInfo, names map[string]string) *ProcessSnapshot {
+	s := &ProcessSnapshot{
+		owners:  make(map[portKey]processOwner, len(entries)),
+		holders: make(map[portKey][]string, len(entries)),
+		names:   names,
+	}
+	if s.names == nil {
+		s.names = make(map[string]string)
+	}
+
+	for _, e := range entries {
+		if e.PID == "" || e.PID == "Unknown" {
+			continue
+		}
+		key := portKey{Protocol: e.Protocol, Port: e.Port}
+		if !slices.Contains(s.holders[key], e.PID) {
+			s.holders[key] = append(s.holders[key], e.PID)
+		}
+		if _, seen := s.owners[key]; seen {
+			continue
+		}
+
+		process := e.Process
+		if process == "" || process == "Unknown" {
+			if name, ok := s.names[e.PID]; ok {
+				process = name
+			} else {
+				process = "Unknown"
+			}
+		} else if _, ok := s.names[e.PID]; !ok {
+			s.names[e.PID] = process
+		}
+		s.owners[key] = processOwner{PID: e.PID, Process: process}
+	}
+
+	return s
+}
+
+// Lookup returns the PID and process name holding a port
+func (s *ProcessSnapshot) Lookup(protocol string, port int) (string, string, bool) {
+	owner, ok := s.owners[portKey{Protocol: protocol, Port: port}]
+	return owner.PID, owner.Process, ok
+}
+
+// PIDs returns every process holding a port, in the order they were found
+func (s *ProcessSnapshot) PIDs(protocol string, port int) []string {
+	return s.holders[portKey{Protocol: protocol, Port: port}]
+}
+
+// Ports returns every port a PID holds, in ascending order
+func (s *ProcessSnapshot) Ports(pid string) []int {
+	var ports []int
+	for key, pids := range s.holders {
+		if slices.Contains(pids, pid) && !slices.Contains(ports, key.Port) {
+			ports = append(ports, key.Port)
+		}
+	}
+	slices.Sort(ports)
+	return ports
+}
+
+// ProcessName returns the process name recorded for a PID
+func (s *ProcessSnapshot) ProcessName(pid string) (string, bool) {
+	name, ok := s.names[pid]
+	return name, ok
+}
+
+// getProcessSnapshot returns the cached snapshot, taking a new one when the
+// cache has expired. Concurrent callers share a single refresh.
+func getProcessSnapshot(ctx context.Context) (*ProcessSnapshot, error) {
+	snapshotCacheMu.Lock()
+	defer snapshotCacheMu.Unlock()
+
+	if snapshotCache != nil && time.Since(snapshotCacheTime) < snapshotCacheTTL {
+		return snapshotCache, nil
+	}
+
+	snapshot, err := takeProcessSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotCache = snapshot
+	snapshotCacheTime = time.Now()
+	return snapshot, nil
+}
+
+// clearProcessCache drops the cached snapshot after process state changed
+func clearProcessCache() {
+	snapshotCacheMu.Lock()
+	snapshotCache = nil
+	snapshotCacheTime = time.Time{}
+	snapshotCacheMu.Unlock()
+}
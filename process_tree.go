@@ -0,0 +1,292 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/user"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// processEntry is one row of the system process list
+type processEntry struct {
+	PID  int
+	PPID int
+	Name string
+	User string
+	Exe  string // executable path, empty when the OS does not reveal it
+}
+
+// ProcessNode is a process in a ProcessTree
+type ProcessNode struct {
+	PID      int
+	PPID     int
+	Name     string
+	User     string
+	Exe      string
+	Parent   *ProcessNode
+	Children []*ProcessNode
+}
+
+// ProcessTree links every running process to its parent and children
+type ProcessTree struct {
+	nodes map[int]*ProcessNode
+}
+
+// sessionBoundaries are processes that start a user's session - shells,
+// terminals, multiplexers and init systems. SessionRoot never climbs past
+// them, so "kill from root" stops at npm/nodemon/air rather than the shell.
+var sessionBoundaries = map[string]bool{
+	"init": true, "systemd": true, "launchd": true, "login": true, "sshd": true,
+	"su": true, "sudo": true, "bash": true, "zsh": true, "fish": true, "ksh": true,
+	"tcsh": true, "csh": true, "nu": true, "pwsh": true, "tmux": true,
+	"tmux: server": true, "screen": true, "gnome-terminal-": true,
+	"gnome-terminal-server": true, "konsole": true, "xterm": true,
+	"alacritty": true, "kitty": true, "wezterm-gui": true, "iTerm2": true,
+	"Terminal": true, "code": true, "Code Helper": true,
+	"explorer.exe": true, "cmd.exe": true, "powershell.exe": true,
+	"pwsh.exe": true, "WindowsTerminal.exe": true, "conhost.exe": true,
+	"services.exe": true, "svchost.exe": true, "wininit.exe": true,
+}
+
+// LoadProcessTree lists every process and links them by parent PID
+func LoadProcessTree() (*ProcessTree, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), AppConfig().CommandTimeout)
+	defer cancel()
+
+	entries, err := listProcesses(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+	return buildProcessTree(entries), nil
+}
+
+// buildProcessTree links entries by PPID. Processes whose parent is not in
+// the list become roots, and so does the lowest PID of any parent cycle.
+func buildProcessTree(entries []processEntry) *ProcessTree {
+	t := &ProcessTree{nodes: make(map[int]*ProcessNode, len(entries))}
+	pids := make([]int, 0, len(entries))
+	for _, e := range entries {
+		t.nodes[e.PID] = &ProcessNode{PID: e.PID, PPID: e.PPID, Name: e.Name, User: e.User, Exe: e.Exe}
+		pids = append(pids, e.PID)
+	}
+	for _, n := range t.nodes {
+		// PID 0 is its own parent on some systems
+		if parent, ok := t.nodes[n.PPID]; ok && n.PPID != n.PID {
+			n.Parent = parent
+			parent.Children = append(parent.Children, n)
+		}
+	}
+
+	// A PID reused while the listing was taken can make a process its own
+	// ancestor; cut such cycles so walking the tree terminates
+	sort.Ints(pids)
+	for _, pid := range pids {
+		n := t.nodes[pid]
+		seen := make(map[*ProcessNode]bool)
+		for p := n.Parent; p != nil && !seen[p]; p = p.Parent {
+			if p == n {
+				n.Parent.Children = slices.DeleteFunc(n.Parent.Children, func(c *ProcessNode) bool { return c == n })
+				n.Parent = nil
+				break
+			}
+			seen[p] = true
+		}
+	}
+
+	for _, n := range t.nodes {
+		sort.Slice(n.Children, func(i, j int) bool {
+			return n.Children[i].PID < n.Children[j].PID
+		})
+	}
+	return t
+}
+
+// Ancestors returns the parent chain of pid, nearest parent first
+func (t *ProcessTree) Ancestors(pid int) []*ProcessNode {
+	var chain []*ProcessNode
+	n := t.nodes[pid]
+	for n != nil && n.Parent != nil {
+		n = n.Parent
+		chain = append(chain, n)
+	}
+	return chain
+}
+
+// Subtree returns pid and all its descendants, parents before children so
+// supervisors are stopped before they can respawn their workers
+func (t *ProcessTree) Subtree(pid int) []*ProcessNode {
+	root := t.nodes[pid]
+	if root == nil {
+		return nil
+	}
+	var nodes []*ProcessNode
+	var walk func(n *ProcessNode)
+	walk = func(n *ProcessNode) {
+		nodes = append(nodes, n)
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+	return nodes
+}
+
+// SessionRoot returns the top-most ancestor of pid that still belongs to the
+// current user's session: it climbs while the parent has the same owner and
+// is not a shell, terminal or init process. It returns the process itself
+// when no such ancestor exists.
+func (t *ProcessTree) SessionRoot(pid int) *ProcessNode {
+	n := t.nodes[pid]
+	if n == nil {
+		return nil
+	}
+
+	currentUser := ""
+	if u, err := user.Current(); err == nil {
+		currentUser = u.Username
+	}
+
+	for n.Parent != nil {
+		parent := n.Parent
+		if parent.PID <= 1 || sessionBoundaries[parent.Name] {
+			break
+		}
+		// Windows listings carry no owner - only compare when both are known
+		if parent.User != "" && currentUser != "" && !sameUser(parent.User, currentUser) {
+			break
+		}
+		n = parent
+	}
+	return n
+}
+
+// sameUser compares user names, ignoring a Windows DOMAIN\ prefix
+func sameUser(a, b string) bool {
+	trim := func(s string) string {
+		if i := strings.LastIndexByte(s, '\\'); i >= 0 {
+			return s[i+1:]
+		}
+		return s
+	}
+	return strings.EqualFold(trim(a), trim(b))
+}
+
+// Render draws the ancestor chain of pid and its subtree, marking pid
+// and the session root
+func (t *ProcessTree) Render(pid int) string {
+	n := t.nodes[pid]
+	if n == nil {
+		return fmt.Sprintf("PID %d is no longer running", pid)
+	}
+	root := t.SessionRoot(pid)
+
+	label := func(p *ProcessNode) string {
+		s := fmt.Sprintf("%s (%d)", p.Name, p.PID)
+		if p.PID == pid {
+			s += "  ◀"
+		}
+		if root != nil && p.PID == root.PID && p.PID != pid {
+			s += "  [session root]"
+		}
+		return s
+	}
+
+	var b strings.Builder
+	ancestors := t.Ancestors(pid)
+	depth := 0
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		writeTreeLine(&b, depth, label(ancestors[i]))
+		depth++
+	}
+
+	var walk func(p *ProcessNode, d int)
+	walk = func(p *ProcessNode, d int) {
+		writeTreeLine(&b, d, label(p))
+		for _, c := range p.Children {
+			walk(c, d+1)
+		}
+	}
+	walk(n, depth)
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// writeTreeLine writes one indented tree line
+func writeTreeLine(b *strings.Builder, depth int, text string) {
+	if depth > 0 {
+		b.WriteString(strings.Repeat("   ", depth-1))
+		b.WriteString("└─ ")
+	}
+	b.WriteString(text)
+	b.WriteByte('\n')
+}
+
+// KillProcessTree terminates pid and all of its descendants, parents first,
+// using the given kill policy. With the graceful policy every process gets
+// SIGTERM up front and only the survivors of the shared grace period are
+// killed. Processes that already exited, such as children that went down
+// with their supervisor, are not reported as failures.
+func KillProcessTree(pid, policy string) error {
+	pidNum, err := strconv.Atoi(pid)
+	if err != nil || pidNum <= 0 {
+		return fmt.Errorf("invalid PID: %q", pid)
+	}
+
+	tree, err := LoadProcessTree()
+	if err != nil {
+		return err
+	}
+	nodes := tree.Subtree(pidNum)
+	if len(nodes) == 0 {
+		return fmt.Errorf("process %s is not running", pid)
+	}
+
+	// Protected processes are left running and reported; the rest of the
+	// tree is still terminated
+	var errs []error
+	targets := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		entry := processEntry{PID: n.PID, PPID: n.PPID, Name: n.Name, User: n.User, Exe: n.Exe}
+		if reason := protectionReason(entry); reason != "" {
+			err := fmt.Errorf("%w: %s", ErrProtectedProcess, reason)
+			beginAudit(n.PID).finish("", err)
+			errs = append(errs, err)
+			continue
+		}
+		targets = append(targets, strconv.Itoa(n.PID))
+	}
+
+	if policy == KillPolicyGraceful {
+		audits := make(map[string]*AuditEntry, len(targets))
+		for _, target := range targets {
+			pidNum, _ := strconv.Atoi(target)
+			audits[target] = beginAudit(pidNum)
+			// Errors surface below if the process outlives the grace period
+			_ = sendTermination(target, false)
+		}
+		clearProcessCache()
+		survivors := waitForExit(AppConfig().KillGracePeriod, targets...)
+
+		// Survivors are audited again when they are force-killed below
+		for _, target := range targets {
+			if !slices.Contains(survivors, target) {
+				audits[target].finish(SignalTerm, nil)
+			}
+		}
+		targets = survivors
+	}
+
+	for _, target := range targets {
+		if !isProcessRunning(target) {
+			continue
+		}
+		if err := KillProcessWithPolicy(target, KillPolicyForce); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,54 @@
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// listProcesses reads name, parent, owner and executable of every process
+// from /proc
+func listProcesses(_ context.Context) ([]processEntry, error) {
+	dirs, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+
+	usernames := make(map[string]string)
+	var entries []processEntry
+	for _, dir := range dirs {
+		pid, err := strconv.Atoi(dir.Name())
+		if err != nil || pid <= 0 {
+			continue
+		}
+
+		// The process may exit while we walk - skip it
+		status, err := readProcStatus(filepath.Join("/proc", dir.Name()))
+		if err != nil {
+			continue
+		}
+
+		e := processEntry{PID: pid, Name: status["Name"]}
+		e.PPID, _ = strconv.Atoi(status["PPid"])
+		// Unreadable for other users' processes without root
+		e.Exe, _ = os.Readlink(filepath.Join("/proc", dir.Name(), "exe"))
+		if uids := strings.Fields(status["Uid"]); len(uids) > 0 {
+			name, cached := usernames[uids[0]]
+			if !cached {
+				name = uids[0]
+				if u, err := user.LookupId(uids[0]); err == nil {
+					name = u.Username
+				}
+				usernames[uids[0]] = name
+			}
+			e.User = name
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
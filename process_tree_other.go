@@ -0,0 +1,40 @@
+//go:build !linux && !windows
+
+package main
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// listProcesses runs ps once for every process
+func listProcesses(ctx context.Context) ([]processEntry, error) {
+	output, err := newCommand(ctx, "ps", "-A", "-o", "pid=,ppid=,user=,comm=").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []processEntry
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		ppid, _ := strconv.Atoi(fields[1])
+
+		// comm is the executable path on macOS and may contain spaces
+		name := strings.Join(fields[3:], " ")
+		exe := ""
+		if i := strings.LastIndexByte(name, '/'); i >= 0 {
+			exe = name
+			name = name[i+1:]
+		}
+		entries = append(entries, processEntry{PID: pid, PPID: ppid, User: fields[2], Name: name, Exe: exe})
+	}
+	return entries, nil
+}
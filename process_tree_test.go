@@ -0,0 +1,148 @@
+package main
+
+import (
+	"os/user"
+	"reflect"
+	"testing"
+)
+
+// nodePIDs lists the PIDs of nodes in order
+func nodePIDs(nodes []*ProcessNode) []int {
+	var pids []int
+	for _, n := range nodes {
+		pids = append(pids, n.PID)
+	}
+	return pids
+}
+
+func TestBuildProcessTree(t *testing.T) {
+	tests := []struct {
+		name          string
+		entries       []processEntry
+		pid           int
+		wantAncestors []int
+		wantSubtree   []int
+	}{
+		{
+			name: "missing parent becomes a root",
+			entries: []processEntry{
+				{PID: 300, PPID: 999, Name: "node"},
+				{PID: 310, PPID: 300, Name: "esbuild"},
+			},
+			pid:         300,
+			wantSubtree: []int{300, 310},
+		},
+		{
+			name: "own parent",
+			entries: []processEntry{
+				{PID: 0, PPID: 0, Name: "kernel_task"},
+				{PID: 1, PPID: 0, Name: "launchd"},
+			},
+			pid:           1,
+			wantAncestors: []int{0},
+			wantSubtree:   []int{1},
+		},
+		{
+			name: "cycle is cut at its lowest PID",
+			entries: []processEntry{
+				{PID: 20, PPID: 10, Name: "b"},
+				{PID: 10, PPID: 20, Name: "a"},
+				{PID: 30, PPID: 20, Name: "c"},
+			},
+			pid:           30,
+			wantAncestors: []int{20, 10},
+			wantSubtree:   []int{30},
+		},
+		{
+			name: "cycle root keeps its subtree",
+			entries: []processEntry{
+				{PID: 20, PPID: 10, Name: "b"},
+				{PID: 10, PPID: 20, Name: "a"},
+				{PID: 30, PPID: 20, Name: "c"},
+			},
+			pid:         10,
+			wantSubtree: []int{10, 20, 30},
+		},
+		{
+			name:    "unknown PID",
+			entries: []processEntry{{PID: 1, Name: "init"}},
+			pid:     42,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := buildProcessTree(tt.entries)
+			if got := nodePIDs(tree.Ancestors(tt.pid)); !reflect.DeepEqual(got, tt.wantAncestors) {
+				t.Errorf("Ancestors(%d) = %v, want %v", tt.pid, got, tt.wantAncestors)
+			}
+			if got := nodePIDs(tree.Subtree(tt.pid)); !reflect.DeepEqual(got, tt.wantSubtree) {
+				t.Errorf("Subtree(%d) = %v, want %v", tt.pid, got, tt.wantSubtree)
+			}
+		})
+	}
+}
+
+func TestProcessTreeSubtreeOrder(t *testing.T) {
+	// Parents come before their children, so a supervisor is stopped before
+	// it can respawn its workers; siblings are in PID order
+	tree := buildProcessTree([]processEntry{
+		{PID: 200, PPID: 100, Name: "nodemon"},
+		{PID: 300, PPID: 200, Name: "node"},
+		{PID: 250, PPID: 200, Name: "node"},
+		{PID: 310, PPID: 300, Name: "esbuild"},
+		{PID: 100, PPID: 1, Name: "bash"},
+	})
+	want := []int{200, 250, 300, 310}
+	if got := nodePIDs(tree.Subtree(200)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Subtree(200) = %v, want %v", got, want)
+	}
+}
+
+func TestProcessTreeSessionRoot(t *testing.T) {
+	current, err := user.Current()
+	if err != nil {
+		t.Skipf("current user unknown: %v", err)
+	}
+	me, other := current.Username, current.Username+"-other"
+
+	tree := buildProcessTree([]processEntry{
+		{PID: 1, PPID: 0, Name: "systemd", User: "root"},
+		{PID: 100, PPID: 1, Name: "bash", User: me},
+		{PID: 200, PPID: 100, Name: "npm", User: me},
+		{PID: 300, PPID: 200, Name: "node", User: me},
+		{PID: 500, PPID: 1, Name: "supervisord", User: other},
+		{PID: 600, PPID: 500, Name: "node", User: me},
+		{PID: 700, PPID: 400, Name: "node", User: me},
+		{PID: 400, PPID: 1, Name: "air", User: me},
+		// Windows listings may carry no owner
+		{PID: 820, PPID: 810, Name: "node.exe"},
+		{PID: 810, PPID: 800, Name: "npm.exe"},
+		{PID: 800, PPID: 4, Name: "cmd.exe"},
+	})
+
+	tests := []struct {
+		name string
+		pid  int
+		want int // 0 for no node
+	}{
+		{name: "stops below the login shell", pid: 300, want: 200},
+		{name: "child of the shell is its own root", pid: 200, want: 200},
+		{name: "stops below init", pid: 700, want: 400},
+		{name: "stops at another user's process", pid: 600, want: 600},
+		{name: "unknown owners are not compared", pid: 820, want: 810},
+		{name: "unknown PID", pid: 42, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := 0
+			if root := tree.SessionRoot(tt.pid); root != nil {
+				got = root.PID
+			}
+			if got != tt.want {
+				t.Errorf("SessionRoot(%d) = %d, want %d", tt.pid, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/windows"
+)
+
+// listProcesses queries Win32_Process once for every process. Owners come
+// from each process token rather than GetOwner, which costs a CIM call per
+// process, and each account SID is looked up once per listing.
+func listProcesses(ctx context.Context) ([]processEntry, error) {
+	output, err := newCommand(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command",
+		"Get-CimInstance Win32_Process | Select-Object ProcessId,ParentProcessId,Name,ExecutablePath | ConvertTo-Csv -NoTypeInformation").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := csv.NewReader(strings.NewReader(string(output))).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []processEntry
+	accounts := make(map[string]string) // SID -> DOMAIN\user
+	for i, record := range records {
+		// Skip the header row
+		if i == 0 || len(record) < 4 {
+			continue
+		}
+		pid, err := strconv.Atoi(record[0])
+		if err != nil {
+			continue
+		}
+		ppid, _ := strconv.Atoi(record[1])
+		entries = append(entries, processEntry{
+			PID:  pid,
+			PPID: ppid,
+			Name: record[2],
+			Exe:  record[3],
+			User: processUser(uint32(pid), accounts),
+		})
+	}
+	return entries, nil
+}
+
+// processUser returns the DOMAIN\user owning a process, or "" when its token
+// cannot be opened - as for protected system processes without elevation.
+// Account names are cached in accounts by SID.
+func processUser(pid uint32, accounts map[string]string) string {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return ""
+	}
+	defer windows.CloseHandle(h)
+
+	var token windows.Token
+	if err := windows.OpenProcessToken(h, windows.TOKEN_QUERY, &token); err != nil {
+		return ""
+	}
+	defer token.Close()
+
+	tokenUser, err := token.GetTokenUser()
+	if err != nil {
+		return ""
+	}
+	sid := tokenUser.User.Sid.String()
+	if name, cached := accounts[sid]; cached {
+		return name
+	}
+	name := ""
+	if account, domain, _, err := tokenUser.User.Sid.LookupAccount(""); err == nil {
+		name = account
+		if domain != "" {
+			name = domain + `\` + account
+		}
+	}
+	accounts[sid] = name
+	return name
+}
@@ -0,0 +1,165 @@
+//go:build !windows
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+func newCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, name, args...)
+}
+
+func netstatListenOutput(ctx context.Context) (string, error) {
+	if runtime.GOOS == "linux" {
+		output, err := newCommand(ctx, "netstat", "-tulnp").Output()
+		return string(output), err
+	}
+
+	// BSD netstat filters by a single protocol per invocation
+	var combined strings.Builder
+	for _, proto := range []string{"tcp", "udp"} {
+		output, err := newCommand(ctx, "netstat", "-an", "-p", proto).Output()
+		if err != nil {
+			return "", err
+		}
+		combined.Write(output)
+	}
+	return combined.String(), nil
+}
+
+// takeProcessSnapshot walks /proc on Linux and otherwise runs lsof once for
+// every socket
+func takeProcessSnapshot(ctx context.Context) (*ProcessSnapshot, error) {
+	if procNetAvailable() {
+		return procfsSnapshot()
+	}
+
+	output, err := runLsof(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newProcessSnapshot(parseLsofListen(output), nil), nil
+}
+
+// detachedProcAttr starts a process in its own session so it outlives
+// DevPorts Pro and its terminal
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
+
+// signalProcess sends SIGTERM, or SIGKILL when force is set
+func signalProcess(pid int, force bool) error {
+	sig := syscall.SIGTERM
+	if force {
+		sig = syscall.SIGKILL
+	}
+	return signalError(syscall.Kill(pid, sig))
+}
+
+// elevatedTerminateScript sends SIGTERM to $1, waits up to $2 tenths of a
+// second for it to exit and then sends SIGKILL, exiting with
+// elevatedForcedExit when it had to
+const elevatedTerminateScript = `kill -TERM "$1" || exit
+n=$2
+while [ "$n" -gt 0 ] && kill -0 "$1" 2>/dev/null; do sleep 0.1; n=$((n - 1)); done
+kill -0 "$1" 2>/dev/null || exit 0
+kill -KILL "$1" && exit %d`
+
+// elevatedTerminate sends SIGTERM and, after grace, SIGKILL - or SIGKILL
+// straight away when grace is zero - in a single run of pkexec, which shows
+// a graphical password prompt, or sudo -n, which only succeeds while sudo
+// has cached credentials. forced reports whether SIGKILL was sent.
+func elevatedTerminate(ctx context.Context, pid int, grace time.Duration) (forced bool, err error) {
+	args := []string{"kill", "-KILL", strconv.Itoa(pid)}
+	if grace > 0 {
+		args = []string{"sh", "-c", fmt.Sprintf(elevatedTerminateScript, elevatedForcedExit), "sh",
+			strconv.Itoa(pid), strconv.Itoa(int(grace / (100 * time.Millisecond)))}
+	}
+
+	err = runElevated(ctx, pid, args)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == elevatedForcedExit {
+		return true, nil
+	}
+	return grace == 0, err
+}
+
+// runElevated runs a kill command with administrator rights and maps its
+// failures to the typed errors. The elevatedForcedExit status is passed
+// through as an *exec.ExitError.
+func runElevated(ctx context.Context, pid int, args []string) error {
+	hasDisplay := os.Getenv("DISPLAY") != "" || os.Getenv("WAYLAND_DISPLAY") != ""
+	if _, err := exec.LookPath("pkexec"); err == nil && hasDisplay {
+		output, err := newCommand(ctx, "pkexec", args...).CombinedOutput()
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			switch exitErr.ExitCode() {
+			case elevatedForcedExit:
+				return err
+			case 126:
+				// The authentication dialog was dismissed
+				return ErrElevationCancelled
+			case 127:
+				return fmt.Errorf("%w: not authorized by polkit", ErrPermissionDenied)
+			}
+		}
+		return elevatedKillError(pid, output, err)
+	}
+
+	if _, err := exec.LookPath("sudo"); err != nil {
+		return errors.New("neither pkexec nor sudo is available")
+	}
+	output, err := newCommand(ctx, "sudo", append([]string{"-n"}, args...)...).CombinedOutput()
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == elevatedForcedExit {
+		return err
+	}
+	if err != nil && strings.Contains(string(output), "password") {
+		return fmt.Errorf("%w: sudo needs a password - run \"sudo -v\" in a terminal first", ErrPermissionDenied)
+	}
+	return elevatedKillError(pid, output, err)
+}
+
+// elevatedKillError maps a failed elevated kill(1) to the typed errors
+func elevatedKillError(pid int, output []byte, err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := strings.TrimSpace(string(output))
+	if strings.Contains(strings.ToLower(msg), "no such process") {
+		return fmt.Errorf("%w: PID %d", ErrNoSuchProcess, pid)
+	}
+	if msg != "" {
+		return fmt.Errorf("elevated kill failed: %s", msg)
+	}
+	return fmt.Errorf("elevated kill failed: %w", err)
+}
+
+// processRunning probes the PID with signal 0, which checks for existence
+// and permission without delivering anything
+func processRunning(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	// EPERM means the process exists but belongs to another user
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// signalError maps errno values from kill(2) to the typed process errors
+func signalError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, syscall.EPERM):
+		return ErrPermissionDenied
+	case errors.Is(err, syscall.ESRCH):
+		return ErrNoSuchProcess
+	}
+	return err
+}
@@ -0,0 +1,198 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf16"
+
+	"golang.org/x/sys/windows"
+)
+
+// newCommand builds an external command that does not flash a console window
+func newCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	return cmd
+}
+
+func netstatListenOutput(ctx context.Context) (string, error) {
+	output, err := newCommand(ctx, "netstat", "-ano").Output()
+	return string(output), err
+}
+
+// takeProcessSnapshot runs netstat once for every socket and tasklist once
+// for every process name
+func takeProcessSnapshot(ctx context.Context) (*ProcessSnapshot, error) {
+	output, err := netstatListenOutput(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := newCommand(ctx, "tasklist", "/fo", "csv", "/nh").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	return newProcessSnapshot(parseNetstatListen(output), parseTasklist(string(tasks))), nil
+}
+
+// parseTasklist maps PIDs to image names from `tasklist /fo csv /nh` output:
+//
+//	"node.exe","1234","Console","1","45,000 K"
+func parseTasklist(output string) map[string]string {
+	names := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "\",\"")
+		if len(fields) < 2 {
+			continue
+		}
+		names[strings.Trim(fields[1], "\"")] = strings.Trim(fields[0], "\"")
+	}
+	return names
+}
+
+// detachedProcAttr starts a process without a console and outside DevPorts
+// Pro's process group, so it outlives the app
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: windows.CREATE_NEW_PROCESS_GROUP | windows.DETACHED_PROCESS,
+	}
+}
+
+// stillActive is the exit code GetExitCodeProcess reports for a live process
+const stillActive = 259
+
+// signalProcess asks a process to exit, or terminates it when force is set.
+// Windows has no SIGTERM: the graceful request is taskkill without /F, which
+// posts WM_CLOSE to the process's windows.
+func signalProcess(pid int, force bool) error {
+	if !force {
+		ctx, cancel := context.WithTimeout(context.Background(), AppConfig().CommandTimeout)
+		defer cancel()
+		if err := newCommand(ctx, "taskkill", "/PID", strconv.Itoa(pid)).Run(); err != nil {
+			if !processRunning(pid) {
+				return fmt.Errorf("%w: PID %d", ErrNoSuchProcess, pid)
+			}
+			// taskkill's messages are localized, so ask the kernel whether
+			// the process may be terminated at all
+			h, openErr := windows.OpenProcess(windows.PROCESS_TERMINATE, false, uint32(pid))
+			if openErr != nil {
+				return signalError(openErr)
+			}
+			windows.CloseHandle(h)
+			return fmt.Errorf("taskkill refused to stop PID %d: %w", pid, err)
+		}
+		return nil
+	}
+
+	h, err := windows.OpenProcess(windows.PROCESS_TERMINATE, false, uint32(pid))
+	if err != nil {
+		return signalError(err)
+	}
+	defer windows.CloseHandle(h)
+	return signalError(windows.TerminateProcess(h, 1))
+}
+
+// elevatedRunScript runs an encoded PowerShell script through the "runas"
+// verb, which shows the UAC prompt, and exits with its exit code - or 1223
+// (ERROR_CANCELLED) when the prompt is declined
+const elevatedRunScript = `try {
+  $p = Start-Process -FilePath powershell -ArgumentList '-NoProfile','-NonInteractive','-EncodedCommand','%s' -Verb RunAs -WindowStyle Hidden -Wait -PassThru -ErrorAction Stop
+} catch { exit 1223 }
+exit $p.ExitCode`
+
+// elevatedTerminateScript asks PID %[1]d to exit with taskkill, waits up to
+// %[2]d ms and forces it with taskkill /F, exiting with %[3]d when it had
+// to. A refused graceful request - console processes cannot be closed
+// without /F - goes straight on to the forced kill.
+const elevatedTerminateScript = `taskkill /PID %[1]d | Out-Null
+if ($LASTEXITCODE -eq 128) { exit 128 }
+$deadline = (Get-Date).AddMilliseconds(%[2]d)
+while ((Get-Process -Id %[1]d -ErrorAction SilentlyContinue) -and (Get-Date) -lt $deadline) { Start-Sleep -Milliseconds 100 }
+if (-not (Get-Process -Id %[1]d -ErrorAction SilentlyContinue)) { exit 0 }
+taskkill /PID %[1]d /F | Out-Null
+if ($LASTEXITCODE -ne 0) { exit $LASTEXITCODE }
+exit %[3]d`
+
+// elevatedForceScript forces PID %d with taskkill /F
+const elevatedForceScript = `taskkill /PID %d /F | Out-Null
+exit $LASTEXITCODE`
+
+// elevatedTerminate runs taskkill and, after grace, taskkill /F - or /F
+// straight away when grace is zero - behind a single UAC prompt. forced
+// reports whether /F was used.
+func elevatedTerminate(ctx context.Context, pid int, grace time.Duration) (forced bool, err error) {
+	script := fmt.Sprintf(elevatedForceScript, pid)
+	if grace > 0 {
+		script = fmt.Sprintf(elevatedTerminateScript, pid, grace.Milliseconds(), elevatedForcedExit)
+	}
+	err = newCommand(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command",
+		fmt.Sprintf(elevatedRunScript, encodePowerShell(script))).Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		switch exitErr.ExitCode() {
+		case elevatedForcedExit:
+			return true, nil
+		case 1223:
+			return false, ErrElevationCancelled
+		case 128:
+			// taskkill: process not found
+			return false, fmt.Errorf("%w: PID %d", ErrNoSuchProcess, pid)
+		}
+		return false, fmt.Errorf("elevated taskkill failed with exit code %d", exitErr.ExitCode())
+	}
+	return grace == 0, err
+}
+
+// encodePowerShell encodes a script for -EncodedCommand, which takes
+// base64 UTF-16LE and so needs no quoting
+func encodePowerShell(script string) string {
+	units := utf16.Encode([]rune(script))
+	raw := make([]byte, 2*len(units))
+	for i, u := range units {
+		binary.LittleEndian.PutUint16(raw[2*i:], u)
+	}
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+func processRunning(pid int) bool {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		// Protected processes exist but refuse even a query handle
+		return errors.Is(err, windows.ERROR_ACCESS_DENIED)
+	}
+	defer windows.CloseHandle(h)
+
+	var code uint32
+	if err := windows.GetExitCodeProcess(h, &code); err != nil {
+		return true
+	}
+	return code == stillActive
+}
+
+// signalError maps Win32 errors from OpenProcess/TerminateProcess to the
+// typed process errors
+func signalError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, windows.ERROR_ACCESS_DENIED):
+		return fmt.Errorf("%w: %v", ErrPermissionDenied, err)
+	case errors.Is(err, windows.ERROR_INVALID_PARAMETER):
+		// OpenProcess rejects PIDs that do not exist
+		return fmt.Errorf("%w: %v", ErrNoSuchProcess, err)
+	}
+	return err
+}
@@ -0,0 +1,49 @@
+//go:build windows
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTasklist(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   map[string]string
+	}{
+		{
+			// `tasklist /fo csv /nh`; memory uses the locale's digit grouping
+			name: "processes",
+			output: "\"System Idle Process\",\"0\",\"Services\",\"0\",\"8 K\"\r\n" +
+				"\"System\",\"4\",\"Services\",\"0\",\"3,280 K\"\r\n" +
+				"\"node.exe\",\"1234\",\"Console\",\"1\",\"45,000 K\"\r\n" +
+				"\"Code - Insiders.exe\",\"9876\",\"Console\",\"1\",\"120.512 K\"\r\n",
+			want: map[string]string{
+				"0":    "System Idle Process",
+				"4":    "System",
+				"1234": "node.exe",
+				"9876": "Code - Insiders.exe",
+			},
+		},
+		{
+			name:   "no tasks",
+			output: "INFO: No tasks are running which match the specified criteria.\r\n",
+			want:   map[string]string{},
+		},
+		{
+			name:   "empty",
+			output: "",
+			want:   map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseTasklist(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTasklist() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+//go:build linux
+
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Socket states as hex-encoded in /proc/net/{tcp,udp}
+const (
+	tcpStateListen = "0A" // TCP_LISTEN
+	udpStateBound  = "07" // TCP_CLOSE - an unconnected, bound UDP socket
+)
+
+// procNetTable is a kernel socket table and the state that marks a listener
+type procNetTable struct {
+	Path        string
+	Protocol    string
+	Family      string
+	ListenState string
+}
+
+// procNetTables are the kernel socket tables for both protocols and families
+var procNetTables = []procNetTable{
+	{"/proc/net/tcp", ProtoTCP, FamilyIPv4, tcpStateListen},
+	{"/proc/net/tcp6", ProtoTCP, FamilyIPv6, tcpStateListen},
+	{"/proc/net/udp", ProtoUDP, FamilyIPv4, udpStateBound},
+	{"/proc/net/udp6", ProtoUDP, FamilyIPv6, udpStateBound},
+}
+
+// procSocket is a single listening socket parsed from /proc/net
+type procSocket struct {
+	Protocol string
+	Family   string
+	Address  string
+	Port     int
+	Inode    string
+}
+
+// procNetAvailable reports whether the kernel socket table can be read
+func procNetAvailable() bool {
+	_, err := os.Stat(procNetTables[0].Path)
+	return err == nil
+}
+
+// ScanProcNet reads the kernel socket tables and returns every listening TCP
+// and bound UDP socket in the configured range. No connections are made and
+// no external binaries are executed.
+func ScanProcNet() ([]PortInfo, error) {
+	sockets, err := readProcNetSockets()
+	if err != nil {
+		return nil, err
+	}
+
+	// Group socket inodes by bind address and port - SO_REUSEPORT servers
+	// hold several sockets on the same one
+	byPort := make(map[portKey][]string)
+	families := make(map[portKey]string)
+	wanted := make(map[string]bool)
+	ports := AppConfig().PortSet()
+	for _, s := range sockets {
+		if !ports.Contains(s.Port) {
+			continue
+		}
+		key := portKey{s.Protocol, s.Address, s.Port}
+		byPort[key] = append(byPort[key], s.Inode)
+		families[key] = s.Family
+		if s.Inode != "0" {
+			wanted[s.Inode] = true
+		}
+	}
+
+	inodePIDs := mapSocketInodes(wanted, false)
+
+	activePorts := make([]PortInfo, 0, len(byPort))
+	for key, inodes := range byPort {
+		pid, process := "Unknown", "Unknown"
+		for _, inode := range inodes {
+			if pids, found := inodePIDs[inode]; found {
+				pid = strconv.Itoa(pids[0])
+				process = readProcComm(pids[0])
+				break
+			}
+		}
+
+		activePorts = append(activePorts, PortInfo{
+			Port:         key.Port,
+			Protocol:     key.Protocol,
+			LocalAddress: key.Address,
+			Family:       families[key],
+			PID:          pid,
+			Process:      process,
+			Status:       "Active",
+		})
+	}
+
+	sortPorts(activePorts)
+
+	return activePorts, nil
+}
+
+// readProcNetSockets reads every listening socket from the kernel tables
+func readProcNetSockets() ([]procSocket, error) {
+	var sockets []procSocket
+	readAny := false
+
+	for _, table := range procNetTables {
+		f, err := os.Open(table.Path)
+		if err != nil {
+			// tcp6/udp6 are missing when IPv6 is disabled - only fail if nothing is readable
+			continue
+		}
+		parsed, err := parseProcNet(f, table)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", table.Path, err)
+		}
+		readAny = true
+		sockets = append(sockets, parsed...)
+	}
+
+	if !readAny {
+		return nil, fmt.Errorf("no readable socket table in /proc/net")
+	}
+	return sockets, nil
+}
+
+// procfsSnapshot maps every listening socket to the processes holding it with
+// a single walk of /proc/<pid>/fd
+func procfsSnapshot() (*ProcessSnapshot, error) {
+	sockets, err := readProcNetSockets()
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(sockets))
+	for _, s := range sockets {
+		if s.Inode != "0" {
+			wanted[s.Inode] = true
+		}
+	}
+	inodePIDs := mapSocketInodes(wanted, true)
+
+	entries := make([]PortInfo, 0, len(sockets))
+	names := make(map[string]string)
+	for _, s := range sockets {
+		for _, p := range inodePIDs[s.Inode] {
+			pid := strconv.Itoa(p)
+			if _, ok := names[pid]; !ok {
+				names[pid] = readProcComm(p)
+			}
+			entries = append(entries, PortInfo{
+				Port:     s.Port,
+				Protocol: s.Protocol,
+				PID:      pid,
+				Process:  names[pid],
+			})
+		}
+	}
+
+	return newProcessSnapshot(entries, names), nil
+}
+
+// parseProcNet extracts listening sockets from a /proc/net/{tcp,udp}{,6} table
+func parseProcNet(r io.Reader, table procNetTable) ([]procSocket, error) {
+	var sockets []procSocket
+
+	scanner := bufio.NewScanner(r)
+	header := true
+	for scanner.Scan() {
+		if header {
+			header = false
+			continue
+		}
+
+		// sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != table.ListenState {
+			continue
+		}
+
+		address, port, err := parseProcAddr(fields[1])
+		if err != nil {
+			return nil, err
+		}
+
+		sockets = append(sockets, procSocket{
+			Protocol: table.Protocol,
+			Family:   table.Family,
+			Address:  address,
+			Port:     port,
+			Inode:    fields[9],
+		})
+	}
+
+	return sockets, scanner.Err()
+}
+
+// parseProcAddr decodes an "ADDR:PORT" hex pair. The kernel prints the
+// address as host-endian 32-bit words, the port in network order.
+func parseProcAddr(addr string) (string, int, error) {
+	idx := strings.LastIndexByte(addr, ':')
+	if idx < 0 {
+		return "", 0, fmt.Errorf("malformed socket address %q", addr)
+	}
+	port, err := strconv.ParseUint(addr[idx+1:], 16, 16)
+	if err != nil {
+		return "", 0, fmt.Errorf("malformed socket port %q: %w", addr, err)
+	}
+
+	raw, err := hex.DecodeString(addr[:idx])
+	if err != nil || (len(raw) != net.IPv4len && len(raw) != net.IPv6len) {
+		return "", 0, fmt.Errorf("malformed socket address %q", addr)
+	}
+	ip := make(net.IP, len(raw))
+	for word := 0; word < len(raw); word += 4 {
+		binary.BigEndian.PutUint32(ip[word:], binary.NativeEndian.Uint32(raw[word:]))
+	}
+
+	return ip.String(), int(port), nil
+}
+
+// mapSocketInodes walks /proc/<pid>/fd and resolves socket inodes to PIDs.
+// A socket inherited across fork is held by several processes; all of them
+// are collected when all is set, otherwise only the first. Processes owned
+// by other users are skipped silently when their fd directory is not
+// readable.
+func mapSocketInodes(wanted map[string]bool, all bool) map[string][]int {
+	result := make(map[string][]int, len(wanted))
+	if len(wanted) == 0 {
+		return result
+	}
+
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return result
+	}
+
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || pid <= 0 {
+			continue
+		}
+
+		fdDir := filepath.Join("/proc", entry.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			inode := strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")
+			if !wanted[inode] {
+				continue
+			}
+			if _, seen := result[inode]; !seen || (all && !slices.Contains(result[inode], pid)) {
+				result[inode] = append(result[inode], pid)
+			}
+		}
+
+		// Stop early once every socket has an owner
+		if !all && len(result) == len(wanted) {
+			break
+		}
+	}
+
+	return result
+}
+
+// readProcComm returns the short process name from /proc/<pid>/comm
+func readProcComm(pid int) string {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "comm"))
+	if err != nil {
+		return "Unknown"
+	}
+	name := strings.TrimSpace(string(data))
+	if name == "" {
+		return "Unknown"
+	}
+	return name
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Captured from /proc/net on Linux 6.18 with listeners on 127.0.0.1:8815,
+// [::]:8816, [::1]:8818 and UDP 0.0.0.0:8817, plus one connection to 8815
+const (
+	procNetTCP = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   1: 0100007F:226F 00000000:0000 0A 00000000:00000001 00:00000000 00000000     0        0 412000 2 00000000fa9a8743 100 0 0 10 0
+   5: 0100007F:CEAE 0100007F:226F 01 00000000:00000000 00:00000000 00000000     0        0 412003 2 00000000892cff41 20 0 0 10 -1
+   9: 0100007F:226F 0100007F:CEAE 01 00000000:00000000 00:00000000 00000000     0        0 0 1 00000000be5c7d91 20 0 0 10 -1
+`
+	procNetTCP6 = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000000000000:2270 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 412001 1 000000000b0cc250 100 0 0 10 0
+   1: 00000000000000000000000001000000:2272 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 412112 1 00000000c589bbce 100 0 0 10 0
+`
+	procNetUDP = `   sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops
+ 1466: 00000000:2271 00000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 412002 2 00000000db0fa8b3 0
+`
+)
+
+func TestParseProcNet(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		table procNetTable
+		want  []procSocket
+	}{
+		{
+			name:  "tcp skips connections",
+			input: procNetTCP,
+			table: procNetTables[0],
+			want: []procSocket{
+				{Protocol: ProtoTCP, Family: FamilyIPv4, Address: "127.0.0.1", Port: 8815, Inode: "412000"},
+			},
+		},
+		{
+			name:  "tcp6",
+			input: procNetTCP6,
+			table: procNetTables[1],
+			want: []procSocket{
+				{Protocol: ProtoTCP, Family: FamilyIPv6, Address: "::", Port: 8816, Inode: "412001"},
+				{Protocol: ProtoTCP, Family: FamilyIPv6, Address: "::1", Port: 8818, Inode: "412112"},
+			},
+		},
+		{
+			name:  "udp",
+			input: procNetUDP,
+			table: procNetTables[2],
+			want: []procSocket{
+				{Protocol: ProtoUDP, Family: FamilyIPv4, Address: "0.0.0.0", Port: 8817, Inode: "412002"},
+			},
+		},
+		{
+			name:  "header only",
+			input: strings.SplitAfter(procNetTCP, "\n")[0],
+			table: procNetTables[0],
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseProcNet(strings.NewReader(tt.input), tt.table)
+			if err != nil {
+				t.Fatalf("parseProcNet() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseProcNet() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseProcAddr(t *testing.T) {
+	tests := []struct {
+		addr     string
+		wantHost string
+		wantPort int
+		wantErr  bool
+	}{
+		{addr: "0100007F:226F", wantHost: "127.0.0.1", wantPort: 8815},
+		{addr: "00000000:0016", wantHost: "0.0.0.0", wantPort: 22},
+		{addr: "00000000000000000000000000000000:2270", wantHost: "::", wantPort: 8816},
+		{addr: "00000000000000000000000001000000:2272", wantHost: "::1", wantPort: 8818},
+		{addr: "0100007F", wantErr: true},
+		{addr: "0100007F:XYZ", wantErr: true},
+		{addr: "0100:226F", wantErr: true},
+		{addr: "ZZ00007F:226F", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			host, port, err := parseProcAddr(tt.addr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseProcAddr(%q) error = %v, wantErr %v", tt.addr, err, tt.wantErr)
+			}
+			if host != tt.wantHost || port != tt.wantPort {
+				t.Errorf("parseProcAddr(%q) = %q, %d, want %q, %d", tt.addr, host, port, tt.wantHost, tt.wantPort)
+			}
+		})
+	}
+}
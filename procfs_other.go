@@ -0,0 +1,21 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+// procNetAvailable is always false outside Linux
+func procNetAvailable() bool {
+	return false
+}
+
+// ScanProcNet is only implemented on Linux; other platforms fall back to
+// the TCP dial prober
+func ScanProcNet() ([]PortInfo, error) {
+	return nil, errors.New("procfs socket tables are only available on Linux")
+}
+
+// procfsSnapshot is only implemented on Linux
+func procfsSnapshot() (*ProcessSnapshot, error) {
+	return nil, errors.New("procfs is only available on Linux")
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Scan backend identifiers accepted by Config.ScanBackend
+const (
+	BackendAuto    = "auto"
+	BackendDial    = "dial"
+	BackendProcfs  = "procfs"
+	BackendLsof    = "lsof"
+	BackendNetstat = "netstat"
+)
+
+// Transport protocols reported in PortInfo.Protocol
+const (
+	ProtoTCP = "TCP"
+	ProtoUDP = "UDP"
+)
+
+// Address families reported in PortInfo.Family
+const (
+	FamilyIPv4 = "IPv4"
+	FamilyIPv6 = "IPv6"
+)
+
+// ScanBackends lists every selectable backend in display order
+var ScanBackends = []string{BackendAuto, BackendDial, BackendProcfs, BackendLsof, BackendNetstat}
+
+// Scanner discovers listening ports on the local machine
+type Scanner interface {
+	// Name identifies the backend in status messages
+	Name() string
+	// Scan returns the active ports sorted by port number
+	Scan(ctx context.Context) ([]PortInfo, error)
+}
+
+// NewScanner returns the scanner for the given backend identifier.
+// BackendAuto picks the fastest backend available on this machine.
+func NewScanner(backend string) (Scanner, error) {
+	switch backend {
+	case BackendAuto, "":
+		return detectScanner(), nil
+	case BackendDial:
+		return DialScanner{}, nil
+	case BackendProcfs:
+		return ProcfsScanner{}, nil
+	case BackendLsof:
+		return LsofScanner{}, nil
+	case BackendNetstat:
+		return NetstatScanner{}, nil
+	default:
+		return nil, fmt.Errorf("unknown scan backend: %q", backend)
+	}
+}
+
+// detectScanner prefers the OS socket table, which reports every TCP and
+// UDP listener whatever address it is bound to, and falls back to probing
+// every port with a TCP dial. lsof is not picked automatically because
+// without root it only lists the current user's sockets.
+func detectScanner() Scanner {
+	if procNetAvailable() {
+		return ProcfsScanner{}
+	}
+	// netstat lists all sockets without elevation: with their PIDs on
+	// Windows, and on macOS and the BSDs with owners taken from lsof
+	if runtime.GOOS != "linux" {
+		if _, err := exec.LookPath("netstat"); err == nil {
+			return NetstatScanner{}
+		}
+	}
+	return DialScanner{}
+}
+
+// ProcfsScanner reads listening sockets from /proc/net (Linux only)
+type ProcfsScanner struct{}
+
+func (ProcfsScanner) Name() string { return BackendProcfs }
+
+func (ProcfsScanner) Scan(ctx context.Context) ([]PortInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return ScanProcNet()
+}
+
+// portKey identifies a listening socket; TCP and UDP ports are independent
+// and the same port may be bound on several addresses
+type portKey struct {
+	Protocol string
+	Address  string
+	Port     int
+}
+
+// finalizePorts filters entries to the configured ports, keeps one entry per
+// protocol, address and port (preferring one with a known PID) and sorts them
+func finalizePorts(entries []PortInfo) []PortInfo {
+	ports := AppConfig().PortSet()
+
+	byPort := make(map[portKey]PortInfo)
+	for _, e := range entries {
+		if !ports.Contains(e.Port) {
+			continue
+		}
+		key := portKey{e.Protocol, e.LocalAddress, e.Port}
+		if existing, seen := byPort[key]; seen && existing.PID != "Unknown" {
+			continue
+		}
+		byPort[key] = e
+	}
+
+	activePorts := make([]PortInfo, 0, len(byPort))
+	for _, p := range byPort {
+		activePorts = append(activePorts, p)
+	}
+	sortPorts(activePorts)
+	return activePorts
+}
+
+// sortPorts orders ports by number, then protocol (TCP before UDP), family
+// and bind address
+func sortPorts(ports []PortInfo) {
+	sort.Slice(ports, func(i, j int) bool {
+		a, b := ports[i], ports[j]
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		if a.Protocol != b.Protocol {
+			return a.Protocol < b.Protocol
+		}
+		if a.Family != b.Family {
+			return a.Family < b.Family
+		}
+		return a.LocalAddress < b.LocalAddress
+	})
+}
+
+// parseAddrPort splits a socket address as printed by lsof or netstat into
+// host and port: "*:3000", "127.0.0.1:80", "[::1]:5432", ":::22" or BSD-style
+// "*.3000". A wildcard host is returned as "*".
+func parseAddrPort(addr string) (string, int, bool) {
+	idx := strings.LastIndexAny(addr, ":.")
+	if idx < 0 || idx == len(addr)-1 {
+		return "", 0, false
+	}
+	port, err := strconv.Atoi(addr[idx+1:])
+	if err != nil || port < 1 || port > 65535 {
+		return "", 0, false
+	}
+
+	host := strings.TrimSuffix(strings.TrimPrefix(addr[:idx], "["), "]")
+	if host == "" {
+		host = "*"
+	}
+	return host, port, true
+}
+
+// normalizeHost resolves a wildcard host to the unspecified address of its
+// family and infers the family when the source does not report it
+func normalizeHost(host, family string) (string, string) {
+	if family == "" {
+		family = FamilyIPv4
+		if strings.Contains(host, ":") {
+			family = FamilyIPv6
+		}
+	}
+	if host == "*" {
+		if family == FamilyIPv6 {
+			return "::", family
+		}
+		return "0.0.0.0", family
+	}
+	return host, family
+}
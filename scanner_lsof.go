@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// LsofScanner lists listening sockets with a single lsof invocation
+type LsofScanner struct{}
+
+func (LsofScanner) Name() string { return BackendLsof }
+
+func (LsofScanner) Scan(ctx context.Context) ([]PortInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, AppConfig().CommandTimeout)
+	defer cancel()
+
+	output, err := runLsof(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return finalizePorts(parseLsofListen(output)), nil
+}
+
+// runLsof lists every internet socket; parseLsofListen picks the listeners.
+// A state filter cannot be used: "-iTCP -sTCP:LISTEN -iUDP" drops every UDP
+// socket and exits 1 even when it prints TCP rows. lsof also exits 1 when
+// nothing matches or some files could not be read, so exit 1 is not an error.
+func runLsof(ctx context.Context) (string, error) {
+	// -n/-P skip DNS and service name lookups so addresses stay numeric
+	output, err := newCommand(ctx, "lsof", "-nP", "-i").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+			return "", fmt.Errorf("lsof failed: %w", err)
+		}
+	}
+	return string(output), nil
+}
+
+// parseLsofListen parses `lsof -nP -i` output, keeping TCP sockets in the
+// LISTEN state and unconnected UDP sockets:
+//
+//	COMMAND   PID USER   FD   TYPE DEVICE SIZE/OFF NODE NAME
+//	node    12345 dev   21u  IPv6 0x1234      0t0  TCP *:3000 (LISTEN)
+//	node    12345 dev   22u  IPv6 0x1235      0t0  TCP [::1]:3000->[::1]:51234 (ESTABLISHED)
+//	statsd    678 dev    5u  IPv4 0x5678      0t0  UDP *:8125
+func parseLsofListen(output string) []PortInfo {
+	var entries []PortInfo
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 9 || fields[0] == "COMMAND" {
+			continue
+		}
+
+		// NODE holds the protocol and is followed by NAME
+		nodeIdx := -1
+		for i := 4; i < len(fields)-1; i++ {
+			if fields[i] == ProtoTCP || fields[i] == ProtoUDP {
+				nodeIdx = i
+				break
+			}
+		}
+		if nodeIdx < 0 {
+			continue
+		}
+
+		// Connected sockets print "local->remote" and are not listeners
+		name := fields[nodeIdx+1]
+		if strings.Contains(name, "->") {
+			continue
+		}
+		// TCP sockets end with their state, e.g. "(LISTEN)" or "(CLOSE_WAIT)"
+		if fields[nodeIdx] == ProtoTCP && (nodeIdx+2 >= len(fields) || fields[nodeIdx+2] != "(LISTEN)") {
+			continue
+		}
+
+		host, port, ok := parseAddrPort(name)
+		if !ok {
+			continue
+		}
+		// TYPE is IPv4 or IPv6
+		address, family := normalizeHost(host, fields[4])
+
+		entries = append(entries, PortInfo{
+			Port:         port,
+			Protocol:     fields[nodeIdx],
+			LocalAddress: address,
+			Family:       family,
+			PID:          fields[1],
+			Process:      fields[0],
+			Status:       "Active",
+		})
+	}
+
+	return entries
+}
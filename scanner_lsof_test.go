@@ -0,0 +1,56 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLsofListen(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []PortInfo
+	}{
+		{
+			// `lsof -nP -i` from lsof 4.95 on Linux; UDP rows end in a space
+			name: "listeners and connections",
+			output: "COMMAND     PID   USER   FD   TYPE DEVICE SIZE/OFF NODE NAME\n" +
+				"python3   11808   root    3u  IPv4 391630      0t0  TCP *:8805 (LISTEN)\n" +
+				"python3   11808   root    4u  IPv4 391631      0t0  UDP 127.0.0.1:8806 \n" +
+				"python3   11808   root    5u  IPv6 391632      0t0  TCP [::1]:8807 (LISTEN)\n" +
+				"python3   11808   root    6u  IPv6 391633      0t0  UDP *:8808 \n" +
+				"python3   11808   root    7u  IPv4 391634      0t0  TCP 127.0.0.1:36428->127.0.0.1:8805 (ESTABLISHED)\n" +
+				"python3   11808   root    8u  IPv4 391635      0t0  UDP 127.0.0.1:44974->127.0.0.1:8806 \n",
+			want: []PortInfo{
+				{Port: 8805, Protocol: ProtoTCP, LocalAddress: "0.0.0.0", Family: FamilyIPv4, PID: "11808", Process: "python3", Status: "Active"},
+				{Port: 8806, Protocol: ProtoUDP, LocalAddress: "127.0.0.1", Family: FamilyIPv4, PID: "11808", Process: "python3", Status: "Active"},
+				{Port: 8807, Protocol: ProtoTCP, LocalAddress: "::1", Family: FamilyIPv6, PID: "11808", Process: "python3", Status: "Active"},
+				{Port: 8808, Protocol: ProtoUDP, LocalAddress: "::", Family: FamilyIPv6, PID: "11808", Process: "python3", Status: "Active"},
+			},
+		},
+		{
+			name: "macOS device addresses and closing sockets",
+			output: "COMMAND   PID USER   FD   TYPE             DEVICE SIZE/OFF NODE NAME\n" +
+				"node    12345 dev   21u  IPv6 0x1234567890abcdef      0t0  TCP *:3000 (LISTEN)\n" +
+				"node    12345 dev   23u  IPv4 0x1234567890abcdf0      0t0  TCP 127.0.0.1:3001 (CLOSE_WAIT)\n" +
+				"mDNSResp  678 _mdnsresponder    5u  IPv4 0x5678      0t0  UDP *:5353\n",
+			want: []PortInfo{
+				{Port: 3000, Protocol: ProtoTCP, LocalAddress: "::", Family: FamilyIPv6, PID: "12345", Process: "node", Status: "Active"},
+				{Port: 5353, Protocol: ProtoUDP, LocalAddress: "0.0.0.0", Family: FamilyIPv4, PID: "678", Process: "mDNSResp", Status: "Active"},
+			},
+		},
+		{
+			name:   "header only",
+			output: "COMMAND     PID   USER   FD   TYPE DEVICE SIZE/OFF NODE NAME\n",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseLsofListen(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLsofListen() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
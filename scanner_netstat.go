@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// NetstatScanner lists listening sockets from netstat output
+type NetstatScanner struct{}
+
+func (NetstatScanner) Name() string { return BackendNetstat }
+
+func (NetstatScanner) Scan(ctx context.Context) ([]PortInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, AppConfig().CommandTimeout)
+	defer cancel()
+
+	output, err := netstatListenOutput(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("netstat failed: %w", err)
+	}
+
+	activePorts := finalizePorts(parseNetstatListen(output))
+
+	// Windows netstat only reports PIDs and BSD netstat reports neither -
+	// fill the gaps from the shared process snapshot
+	if snapshot, err := getProcessSnapshot(ctx); err == nil {
+		for i := range activePorts {
+			fillProcessOwner(&activePorts[i], snapshot)
+		}
+	}
+
+	return activePorts, nil
+}
+
+// fillProcessOwner completes a port's PID and process name from a snapshot
+func fillProcessOwner(p *PortInfo, snapshot *ProcessSnapshot) {
+	if p.PID == "Unknown" {
+		if pid, process, ok := snapshot.Lookup(p.Protocol, p.Port); ok {
+			p.PID, p.Process = pid, process
+		}
+		return
+	}
+	if p.Process == "Unknown" {
+		if name, ok := snapshot.ProcessName(p.PID); ok {
+			p.Process = name
+		}
+	}
+}
+
+// parseNetstatListen parses listening TCP and bound UDP sockets from netstat
+// output in any of the supported formats. Listeners are recognised by their
+// wildcard foreign address rather than the state column, which Windows and
+// net-tools translate (e.g. "ABHÖREN"):
+//
+//	Windows: TCP    0.0.0.0:135    0.0.0.0:0    LISTENING    1234
+//	         UDP    0.0.0.0:123    *:*                       1234
+//	Linux:   tcp    0   0 0.0.0.0:22   0.0.0.0:*    LISTEN     1234/sshd
+//	         udp    0   0 0.0.0.0:68   0.0.0.0:*               567/dhclient
+//	macOS:   tcp4   0   0 *.3000       *.*          LISTEN
+//	         udp4   0   0 *.5353       *.*
+func parseNetstatListen(output string) []PortInfo {
+	var entries []PortInfo
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+
+		var protocol string
+		switch proto := strings.ToLower(fields[0]); {
+		case strings.HasPrefix(proto, "tcp"):
+			protocol = ProtoTCP
+		case strings.HasPrefix(proto, "udp"):
+			protocol = ProtoUDP
+		default:
+			continue
+		}
+
+		// Windows puts the local address right after the protocol, Unix
+		// netstat has Recv-Q and Send-Q columns in between
+		localIdx := 1
+		if !strings.ContainsAny(fields[1], ":.") {
+			localIdx = 3
+		}
+		if localIdx+1 >= len(fields) {
+			continue
+		}
+
+		// Connected sockets, TCP or UDP, have a concrete peer
+		if !isWildcardForeign(fields[localIdx+1]) {
+			continue
+		}
+
+		// TCP rows always have a state column after the foreign address, UDP
+		// rows may; owners start with a digit or are "-" where states never do
+		ownerIdx := localIdx + 2
+		if protocol == ProtoTCP || (ownerIdx < len(fields) && !isNetstatOwner(fields[ownerIdx])) {
+			ownerIdx++
+		}
+
+		host, port, ok := parseAddrPort(fields[localIdx])
+		if !ok {
+			continue
+		}
+		// BSD netstat only tells the families apart by the tcp4/tcp6 suffix
+		family := ""
+		switch {
+		case strings.HasSuffix(fields[0], "4"):
+			family = FamilyIPv4
+		case strings.HasSuffix(fields[0], "6"):
+			family = FamilyIPv6
+		}
+		address, family := normalizeHost(host, family)
+
+		pid, process := "Unknown", "Unknown"
+		if ownerIdx < len(fields) {
+			owner := fields[ownerIdx]
+			if slash := strings.IndexByte(owner, '/'); slash > 0 {
+				// Linux prints "PID/program"
+				pid, process = owner[:slash], owner[slash+1:]
+			} else if owner != "-" {
+				pid = owner
+			}
+		}
+
+		entries = append(entries, PortInfo{
+			Port:         port,
+			Protocol:     protocol,
+			LocalAddress: address,
+			Family:       family,
+			PID:          pid,
+			Process:      process,
+			Status:       "Active",
+		})
+	}
+
+	return entries
+}
+
+// isWildcardForeign reports whether a netstat foreign address has no peer,
+// e.g. "0.0.0.0:0", "[::]:0", "0.0.0.0:*", ":::*", "*:*" or "*.*"
+func isWildcardForeign(addr string) bool {
+	i := strings.LastIndexAny(addr, ":.")
+	if i < 0 {
+		return false
+	}
+	host, port := addr[:i], addr[i+1:]
+	if port != "0" && port != "*" {
+		return false
+	}
+	switch strings.Trim(host, "[]") {
+	case "*", "0.0.0.0", "::", "":
+		return true
+	}
+	return false
+}
+
+// isNetstatOwner reports whether a netstat column is an owner - a PID,
+// "PID/program" or "-" - rather than a socket state
+func isNetstatOwner(field string) bool {
+	return field == "-" || (field != "" && field[0] >= '0' && field[0] <= '9')
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseNetstatListen(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []PortInfo
+	}{
+		{
+			// `netstat -anp` from net-tools on Linux
+			name: "linux",
+			output: "Active Internet connections (servers and established)\n" +
+				"Proto Recv-Q Send-Q Local Address           Foreign Address         State       PID/Program name    \n" +
+				"tcp        0      0 0.0.0.0:8795            0.0.0.0:*               LISTEN      11625/python3       \n" +
+				"tcp        0      0 127.0.0.1:35120         127.0.0.1:48271         ESTABLISHED 10632/claude        \n" +
+				"tcp        0      0 127.0.0.1:35116         127.0.0.1:48271         TIME_WAIT   -                   \n" +
+				"tcp6       0      0 :::22                   :::*                    LISTEN      -                   \n" +
+				"udp        0      0 0.0.0.0:8817            0.0.0.0:*                           11808/python3       \n" +
+				"udp        0      0 127.0.0.1:44974         127.0.0.1:8806          ESTABLISHED 11808/python3       \n",
+			want: []PortInfo{
+				{Port: 8795, Protocol: ProtoTCP, LocalAddress: "0.0.0.0", Family: FamilyIPv4, PID: "11625", Process: "python3", Status: "Active"},
+				{Port: 22, Protocol: ProtoTCP, LocalAddress: "::", Family: FamilyIPv6, PID: "Unknown", Process: "Unknown", Status: "Active"},
+				{Port: 8817, Protocol: ProtoUDP, LocalAddress: "0.0.0.0", Family: FamilyIPv4, PID: "11808", Process: "python3", Status: "Active"},
+			},
+		},
+		{
+			// `netstat -ano` on English Windows
+			name: "windows",
+			output: "\r\nActive Connections\r\n\r\n" +
+				"  Proto  Local Address          Foreign Address        State           PID\r\n" +
+				"  TCP    0.0.0.0:135            0.0.0.0:0              LISTENING       1024\r\n" +
+				"  TCP    127.0.0.1:49670        127.0.0.1:49671        ESTABLISHED     4242\r\n" +
+				"  TCP    [::]:445               [::]:0                 LISTENING       4\r\n" +
+				"  UDP    0.0.0.0:5353           *:*                                    2280\r\n" +
+				"  UDP    [::1]:1900             *:*                                    5012\r\n",
+			want: []PortInfo{
+				{Port: 135, Protocol: ProtoTCP, LocalAddress: "0.0.0.0", Family: FamilyIPv4, PID: "1024", Process: "Unknown", Status: "Active"},
+				{Port: 445, Protocol: ProtoTCP, LocalAddress: "::", Family: FamilyIPv6, PID: "4", Process: "Unknown", Status: "Active"},
+				{Port: 5353, Protocol: ProtoUDP, LocalAddress: "0.0.0.0", Family: FamilyIPv4, PID: "2280", Process: "Unknown", Status: "Active"},
+				{Port: 1900, Protocol: ProtoUDP, LocalAddress: "::1", Family: FamilyIPv6, PID: "5012", Process: "Unknown", Status: "Active"},
+			},
+		},
+		{
+			// German Windows translates the state column
+			name: "windows localized state",
+			output: "\r\nAktive Verbindungen\r\n\r\n" +
+				"  Proto  Lokale Adresse         Remoteadresse          Status           PID\r\n" +
+				"  TCP    0.0.0.0:135            0.0.0.0:0              ABHÖREN         1024\r\n" +
+				"  TCP    127.0.0.1:49670        127.0.0.1:49671        HERGESTELLT     4242\r\n" +
+				"  TCP    [::]:445               [::]:0                 ABHÖREN         4\r\n",
+			want: []PortInfo{
+				{Port: 135, Protocol: ProtoTCP, LocalAddress: "0.0.0.0", Family: FamilyIPv4, PID: "1024", Process: "Unknown", Status: "Active"},
+				{Port: 445, Protocol: ProtoTCP, LocalAddress: "::", Family: FamilyIPv6, PID: "4", Process: "Unknown", Status: "Active"},
+			},
+		},
+		{
+			// `netstat -an` on macOS has no owner column
+			name: "macOS",
+			output: "Active Internet connections (including servers)\n" +
+				"Proto Recv-Q Send-Q  Local Address          Foreign Address        (state)\n" +
+				"tcp4       0      0  127.0.0.1.3000         127.0.0.1.52000        ESTABLISHED\n" +
+				"tcp6       0      0  *.3000                 *.*                    LISTEN\n" +
+				"tcp4       0      0  127.0.0.1.5432         *.*                    LISTEN\n" +
+				"udp4       0      0  *.5353                 *.*\n",
+			want: []PortInfo{
+				{Port: 3000, Protocol: ProtoTCP, LocalAddress: "::", Family: FamilyIPv6, PID: "Unknown", Process: "Unknown", Status: "Active"},
+				{Port: 5432, Protocol: ProtoTCP, LocalAddress: "127.0.0.1", Family: FamilyIPv4, PID: "Unknown", Process: "Unknown", Status: "Active"},
+				{Port: 5353, Protocol: ProtoUDP, LocalAddress: "0.0.0.0", Family: FamilyIPv4, PID: "Unknown", Process: "Unknown", Status: "Active"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseNetstatListen(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseNetstatListen() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+//go:embed web
+var webAssets embed.FS
+
+// RunWeb serves the web interface and REST API until interrupted and
+// returns the process exit code. Every /api request needs the token, given
+// as "Authorization: Bearer TOKEN"; the UI takes it from ?token= once.
+func RunWeb(flags *ConfigFlags, stdout, stderr io.Writer) int {
+	if code, done := loadHeadlessConfig(flags, stdout, stderr); done {
+		return code
+	}
+	if len(flags.Args) > 0 {
+		fmt.Fprintf(stderr, "devports: unexpected arguments: %s\n", strings.Join(flags.Args, " "))
+		return ExitUsage
+	}
+	if flags.WebPort < 1 || flags.WebPort > 65535 {
+		fmt.Fprintf(stderr, "devports: invalid --port: %d (must be 1-65535)\n", flags.WebPort)
+		return ExitUsage
+	}
+
+	token := flags.WebToken
+	if token == "" {
+		token = os.Getenv(EnvWebToken)
+	}
+	if token == "" {
+		var err error
+		if token, err = newWebToken(); err != nil {
+			fmt.Fprintf(stderr, "devports: failed to generate a token: %v\n", err)
+			return ExitFailure
+		}
+	}
+
+	addr := net.JoinHostPort(flags.WebBind, strconv.Itoa(flags.WebPort))
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		fmt.Fprintf(stderr, "devports: %v\n", err)
+		return ExitFailure
+	}
+	if ip := net.ParseIP(flags.WebBind); ip == nil || !ip.IsLoopback() {
+		fmt.Fprintf(stderr, "devports: warning: listening on %s lets anyone with the token on the network kill processes\n", flags.WebBind)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := newWebServer(ctx, token)
+	go server.autoRefresh()
+
+	// Pick up edits to the config files while serving
+	if err := WatchConfig(flags, nil, ctx.Done(), func(cfg *Config, files []string, err error) {
+		if err != nil {
+			fmt.Fprintf(stderr, "devports: config reload failed, keeping the previous configuration: %v\n", err)
+		}
+	}); err != nil {
+		fmt.Fprintf(stderr, "devports: config files are not watched for changes: %v\n", err)
+	}
+
+	httpServer := &http.Server{Handler: server.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(stdout, "DevPorts Pro web interface: http://%s/?token=%s\n", listener.Addr(), token)
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(stderr, "devports: %v\n", err)
+		return ExitFailure
+	}
+	return ExitOK
+}
+
+// newWebToken returns a random 128-bit token in hex
+func newWebToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// webPort is a PortInfo as served by the API, with what the Fyne table
+// derives from it
+type webPort struct {
+	PortInfo
+	Exposed   bool   `json:"exposed"`
+	Protected string `json:"protected,omitempty"` // why the process may not be killed
+}
+
+// webScan is the response of GET /api/ports and POST /api/scan
+type webScan struct {
+	Ports     []webPort `json:"ports"`
+	ScannedAt time.Time `json:"scanned_at"` // zero until the first scan finishes
+	Scanning  bool      `json:"scanning"`
+	Error     string    `json:"error,omitempty"` // why the last scan failed
+}
+
+// webServer serves the REST API from the result of the last scan, so
+// clients polling it do not each start a scan
+type webServer struct {
+	ctx      context.Context // the server's lifetime; scans outlive the requests that start them
+	token    string
+	scanning atomic.Bool
+	scanMu   sync.Mutex // serializes scans
+	mu       sync.RWMutex
+	last     webScan // protected by mu
+}
+
+func newWebServer(ctx context.Context, token string) *webServer {
+	return &webServer{ctx: ctx, token: token, last: webScan{Ports: []webPort{}}}
+}
+
+// Handler routes the API behind the token check and serves the UI
+func (s *webServer) Handler() http.Handler {
+	assets, _ := fs.Sub(webAssets, "web")
+	api := http.NewServeMux()
+	api.HandleFunc("/api/ports", s.handlePorts)
+	api.HandleFunc("/api/ports/", s.handlePort)
+	api.HandleFunc("/api/processes/", s.handleProcess)
+	api.HandleFunc("/api/scan", s.handleScan)
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/", s.requireToken(api))
+	mux.Handle("/", http.FileServer(http.FS(assets)))
+	return mux
+}
+
+// requireToken rejects requests without the bearer token. The token is
+// never accepted from cookies, so other sites cannot make requests with it.
+func (s *webServer) requireToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// scan rescans and stores the result; a scan started meanwhile is waited for.
+// It only stops early when the server shuts down, since other clients share
+// the result.
+func (s *webServer) scan() webScan {
+	s.scanMu.Lock()
+	defer s.scanMu.Unlock()
+	s.scanning.Store(true)
+	defer s.scanning.Store(false)
+
+	ports, err := ScanPorts(s.ctx, ScanOptions{})
+	result := webScan{Ports: make([]webPort, 0, len(ports)), ScannedAt: time.Now()}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	protection, protectionErr := LoadProtectionIndex()
+	for _, p := range ports {
+		wp := webPort{PortInfo: p, Exposed: p.Exposed()}
+		if protectionErr == nil {
+			wp.Protected = protection.Reason(p.PID)
+		}
+		result.Ports = append(result.Ports, wp)
+	}
+
+	s.mu.Lock()
+	s.last = result
+	s.mu.Unlock()
+	return result
+}
+
+// autoRefresh scans at startup and then every AutoRefreshInterval until
+// the server shuts down
+func (s *webServer) autoRefresh() {
+	for {
+		s.scan()
+		select {
+		case <-time.After(AppConfig().AutoRefreshInterval):
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// snapshot returns the last scan
+func (s *webServer) snapshot() webScan {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := s.last
+	result.Scanning = s.scanning.Load()
+	return result
+}
+
+// GET /api/ports lists the ports found by the last scan
+func (s *webServer) handlePorts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.snapshot())
+}
+
+// GET /api/ports/{port} describes the sockets on a port and the processes
+// holding it, looked up afresh
+func (s *webServer) handlePort(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+	port, err := parsePort(strings.TrimPrefix(r.URL.Path, "/api/ports/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	sockets := []webPort{}
+	for _, p := range s.snapshot().Ports {
+		if p.Port == port {
+			sockets = append(sockets, p)
+		}
+	}
+	pids, err := PortHolders(port)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to resolve processes on port %d: %w", port, err))
+		return
+	}
+	if len(pids) == 0 && len(sockets) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("%w %d", ErrPortNotInUse, port))
+		return
+	}
+
+	processes := []*ProcessDetails{}
+	for _, pid := range pids {
+		if details, err := GetProcessDetails(pid); err == nil {
+			processes = append(processes, details)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"port":      port,
+		"sockets":   sockets,
+		"processes": processes,
+	})
+}
+
+// DELETE /api/processes/{pid}[?policy=graceful|force] terminates a process
+func (s *webServer) handleProcess(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeMethodNotAllowed(w, http.MethodDelete)
+		return
+	}
+	pid := strings.TrimPrefix(r.URL.Path, "/api/processes/")
+	if n, err := strconv.Atoi(pid); err != nil || n <= 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid PID: %q", pid))
+		return
+	}
+	policy := r.URL.Query().Get("policy")
+	if policy == "" {
+		policy = AppConfig().KillPolicy
+	}
+	if policy != KillPolicyGraceful && policy != KillPolicyForce {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid policy: %q (must be one of %v)", policy, KillPolicies))
+		return
+	}
+
+	if err := KillProcessWithPolicy(pid, policy); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, ErrNoSuchProcess):
+			status = http.StatusNotFound
+		case errors.Is(err, ErrPermissionDenied), errors.Is(err, ErrProtectedProcess):
+			status = http.StatusForbidden
+		}
+		writeError(w, status, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"pid": pid, "policy": policy, "result": "terminated"})
+}
+
+// POST /api/scan rescans and returns the new result. A client that leaves
+// stops waiting, but the scan carries on and still updates the last result.
+func (s *webServer) handleScan(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+	result := make(chan webScan, 1)
+	go func() { result <- s.scan() }()
+	select {
+	case scan := <-result:
+		writeJSON(w, http.StatusOK, scan)
+	case <-r.Context().Done():
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter, allowed string) {
+	w.Header().Set("Allow", allowed)
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed (use %s)", allowed))
+}
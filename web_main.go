@@ -0,0 +1,24 @@
+//go:build web
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// main for the devports-web build, which serves the web interface and
+// REST API without GUI dependencies
+func main() {
+	flags, err := ParseFlags(os.Args[1:], os.Stderr)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(ExitOK)
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(ExitUsage)
+	}
+	os.Exit(RunWeb(flags, os.Stdout, os.Stderr))
+}
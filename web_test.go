@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const testWebToken = "0123456789abcdef"
+
+// serveAPI sends a request to a fresh web server and decodes the error
+// from the JSON body, if any
+func serveAPI(t *testing.T, method, target, authorization string) (*httptest.ResponseRecorder, string) {
+	t.Helper()
+	req := httptest.NewRequest(method, target, nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	rec := httptest.NewRecorder()
+	newWebServer(context.Background(), testWebToken).Handler().ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Fatalf("%s %s Content-Type = %q, want application/json", method, target, got)
+	}
+	var body struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("%s %s body %q: %v", method, target, rec.Body, err)
+	}
+	return rec, body.Error
+}
+
+func TestWebRequireToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+	}{
+		{name: "missing", wantStatus: http.StatusUnauthorized},
+		{name: "wrong", authorization: "Bearer fedcba9876543210", wantStatus: http.StatusUnauthorized},
+		{name: "prefix of the token", authorization: "Bearer 0123", wantStatus: http.StatusUnauthorized},
+		{name: "without the scheme", authorization: testWebToken, wantStatus: http.StatusUnauthorized},
+		{name: "other scheme", authorization: "Basic " + testWebToken, wantStatus: http.StatusUnauthorized},
+		{name: "valid", authorization: "Bearer " + testWebToken, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, errMsg := serveAPI(t, http.MethodGet, "/api/ports", tt.authorization)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("GET /api/ports status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if (tt.wantStatus == http.StatusUnauthorized) != (errMsg != "") {
+				t.Errorf("GET /api/ports error = %q, want one only when unauthorized", errMsg)
+			}
+		})
+	}
+}
+
+func TestWebHandlerErrors(t *testing.T) {
+	setTestConfig(t, func(*Config) {})
+
+	// A port that was just released has no holders and is in no scan
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	freePort := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		wantStatus int
+		wantAllow  string // the Allow header of a 405
+		wantError  string // part of the error message
+	}{
+		{name: "list with POST", method: http.MethodPost, target: "/api/ports",
+			wantStatus: http.StatusMethodNotAllowed, wantAllow: http.MethodGet, wantError: "use GET"},
+		{name: "port with DELETE", method: http.MethodDelete, target: "/api/ports/3000",
+			wantStatus: http.StatusMethodNotAllowed, wantAllow: http.MethodGet, wantError: "use GET"},
+		{name: "kill with GET", method: http.MethodGet, target: "/api/processes/1234",
+			wantStatus: http.StatusMethodNotAllowed, wantAllow: http.MethodDelete, wantError: "use DELETE"},
+		{name: "scan with GET", method: http.MethodGet, target: "/api/scan",
+			wantStatus: http.StatusMethodNotAllowed, wantAllow: http.MethodPost, wantError: "use POST"},
+		{name: "PID not a number", method: http.MethodDelete, target: "/api/processes/abc",
+			wantStatus: http.StatusBadRequest, wantError: `invalid PID: "abc"`},
+		{name: "PID zero", method: http.MethodDelete, target: "/api/processes/0",
+			wantStatus: http.StatusBadRequest, wantError: `invalid PID: "0"`},
+		{name: "unknown policy", method: http.MethodDelete, target: "/api/processes/1234?policy=nope",
+			wantStatus: http.StatusBadRequest, wantError: `invalid policy: "nope"`},
+		{name: "port out of range", method: http.MethodGet, target: "/api/ports/70000",
+			wantStatus: http.StatusBadRequest, wantError: "invalid port"},
+		{name: "port without holders", method: http.MethodGet, target: "/api/ports/" + strconv.Itoa(freePort),
+			wantStatus: http.StatusNotFound, wantError: ErrPortNotInUse.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, errMsg := serveAPI(t, tt.method, tt.target, "Bearer "+testWebToken)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("%s %s status = %d, want %d", tt.method, tt.target, rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Allow"); got != tt.wantAllow {
+				t.Errorf("%s %s Allow = %q, want %q", tt.method, tt.target, got, tt.wantAllow)
+			}
+			if !strings.Contains(errMsg, tt.wantError) {
+				t.Errorf("%s %s error = %q, want it to mention %q", tt.method, tt.target, errMsg, tt.wantError)
+			}
+		})
+	}
+}